@@ -0,0 +1,239 @@
+package connectivity
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Credentials is the resolved access key pair (plus optional STS token)
+// that a CredentialsProvider hands back to the caller on every request.
+type Credentials struct {
+	AccessKeyId     string
+	AccessKeySecret string
+	SecurityToken   string
+	Expiration      time.Time
+}
+
+// Expired reports whether the credentials are at or past expiry. A zero
+// Expiration means the credentials never expire (e.g. static AK/SK).
+func (c *Credentials) Expired() bool {
+	if c.Expiration.IsZero() {
+		return false
+	}
+	return !time.Now().Before(c.Expiration)
+}
+
+// CredentialsProvider resolves the access key / STS token used to sign
+// requests against Alibaba Cloud APIs. Implementations range from a static
+// AK/SK pair to chains that assume a RAM role or exchange an OIDC token,
+// mirroring the provider chain the community SDKs settled on.
+type CredentialsProvider interface {
+	// Name identifies the provider for error messages and the
+	// `credentials { provider = "..." }` provider block.
+	Name() string
+	// Retrieve returns the current credentials, refreshing them if
+	// necessary. Implementations are expected to cache until near expiry.
+	Retrieve() (*Credentials, error)
+}
+
+// StaticCredentialsProvider wraps an AK/SK pair (optionally with an STS
+// token) that never expires and is returned as-is.
+type StaticCredentialsProvider struct {
+	AccessKeyId     string
+	AccessKeySecret string
+	SecurityToken   string
+}
+
+func (p *StaticCredentialsProvider) Name() string { return "static_ak" }
+
+func (p *StaticCredentialsProvider) Retrieve() (*Credentials, error) {
+	if p.AccessKeyId == "" || p.AccessKeySecret == "" {
+		return nil, fmt.Errorf("connectivity: static credentials provider requires access_key and secret_key")
+	}
+	return &Credentials{
+		AccessKeyId:     p.AccessKeyId,
+		AccessKeySecret: p.AccessKeySecret,
+		SecurityToken:   p.SecurityToken,
+	}, nil
+}
+
+// EnvCredentialsProvider reads ALICLOUD_ACCESS_KEY / ALICLOUD_SECRET_KEY /
+// ALICLOUD_SECURITY_TOKEN from a supplied lookup function, matching the
+// provider's existing environment-variable fallback.
+type EnvCredentialsProvider struct {
+	Lookup func(key string) (string, bool)
+}
+
+func (p *EnvCredentialsProvider) Name() string { return "env" }
+
+func (p *EnvCredentialsProvider) Retrieve() (*Credentials, error) {
+	accessKeyId, ok := p.Lookup("ALICLOUD_ACCESS_KEY")
+	if !ok || accessKeyId == "" {
+		return nil, fmt.Errorf("connectivity: ALICLOUD_ACCESS_KEY is not set")
+	}
+	accessKeySecret, ok := p.Lookup("ALICLOUD_SECRET_KEY")
+	if !ok || accessKeySecret == "" {
+		return nil, fmt.Errorf("connectivity: ALICLOUD_SECRET_KEY is not set")
+	}
+	securityToken, _ := p.Lookup("ALICLOUD_SECURITY_TOKEN")
+	return &Credentials{
+		AccessKeyId:     accessKeyId,
+		AccessKeySecret: accessKeySecret,
+		SecurityToken:   securityToken,
+	}, nil
+}
+
+// RefreshFunc fetches a fresh Credentials from a backing service (ECS RAM
+// role metadata, STS AssumeRole, an OIDC/JWT exchange, ECI metadata, or a
+// CLI profile — see credentials_providers.go for the concrete
+// implementations). RefreshableCredentialsProvider wraps it with caching,
+// periodic background refresh, and single-flight coalescing so concurrent
+// callers near expiry don't all hit the backing service at once.
+type RefreshFunc func() (*Credentials, error)
+
+// RefreshableCredentialsProvider is the common base for every non-static
+// provider in the chain (ecs_ram_role, ram_role_arn, oidc_role_arn,
+// eci_ram_role, profile — constructed by the New*CredentialsProvider
+// functions in credentials_providers.go). It refreshes synchronously the
+// first time and then checks every RefreshInterval in the background,
+// swapping in the new Credentials atomically under a mutex.
+type RefreshableCredentialsProvider struct {
+	name            string
+	refresh         RefreshFunc
+	refreshInterval time.Duration
+	refreshAhead    time.Duration
+
+	mu          sync.Mutex
+	current     *Credentials
+	refreshing  bool
+	refreshedCh chan struct{}
+
+	stopOnce sync.Once
+	stopCh   chan struct{}
+}
+
+// NewRefreshableCredentialsProvider builds a provider that calls refresh to
+// populate credentials, refreshing again refreshAhead before expiry (or
+// every refreshInterval for providers, like profile, whose credentials
+// don't carry an expiry).
+func NewRefreshableCredentialsProvider(name string, refresh RefreshFunc, refreshInterval, refreshAhead time.Duration) *RefreshableCredentialsProvider {
+	p := &RefreshableCredentialsProvider{
+		name:            name,
+		refresh:         refresh,
+		refreshInterval: refreshInterval,
+		refreshAhead:    refreshAhead,
+		stopCh:          make(chan struct{}),
+	}
+	go p.refreshLoop()
+	return p
+}
+
+func (p *RefreshableCredentialsProvider) Name() string { return p.name }
+
+// Retrieve returns cached credentials when they are still fresh. When they
+// are near or past expiry, concurrent callers coalesce onto a single
+// in-flight refresh instead of each calling the backing service.
+func (p *RefreshableCredentialsProvider) Retrieve() (*Credentials, error) {
+	p.mu.Lock()
+	if p.current != nil && !p.nearExpiryLocked() {
+		creds := p.current
+		p.mu.Unlock()
+		return creds, nil
+	}
+	if p.refreshing {
+		ch := p.refreshedCh
+		p.mu.Unlock()
+		<-ch
+		p.mu.Lock()
+		creds := p.current
+		p.mu.Unlock()
+		if creds == nil {
+			return nil, fmt.Errorf("connectivity: %s credentials provider failed to refresh", p.name)
+		}
+		return creds, nil
+	}
+	p.refreshing = true
+	p.refreshedCh = make(chan struct{})
+	p.mu.Unlock()
+
+	creds, err := p.refresh()
+
+	p.mu.Lock()
+	if err == nil {
+		p.current = creds
+	}
+	p.refreshing = false
+	close(p.refreshedCh)
+	cached := p.current
+	p.mu.Unlock()
+
+	if err != nil && cached == nil {
+		return nil, err
+	}
+	return cached, nil
+}
+
+func (p *RefreshableCredentialsProvider) nearExpiryLocked() bool {
+	if p.current.Expiration.IsZero() {
+		return false
+	}
+	return time.Now().Add(p.refreshAhead).After(p.current.Expiration)
+}
+
+func (p *RefreshableCredentialsProvider) refreshLoop() {
+	ticker := time.NewTicker(p.refreshInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			p.mu.Lock()
+			refreshing := p.refreshing
+			noExpiry := p.current != nil && p.current.Expiration.IsZero()
+			p.mu.Unlock()
+			if refreshing {
+				continue
+			}
+			if noExpiry {
+				if creds, err := p.refresh(); err == nil {
+					p.mu.Lock()
+					p.current = creds
+					p.mu.Unlock()
+				}
+				continue
+			}
+			_, _ = p.Retrieve()
+		case <-p.stopCh:
+			return
+		}
+	}
+}
+
+// Close stops the background refresh loop.
+func (p *RefreshableCredentialsProvider) Close() {
+	p.stopOnce.Do(func() { close(p.stopCh) })
+}
+
+// ChainCredentialsProvider tries each provider in order and returns the
+// first one that resolves successfully, matching the `credentials { provider
+// = "chain" }` provider block backed by one or more `assume_role` blocks.
+type ChainCredentialsProvider struct {
+	Providers []CredentialsProvider
+}
+
+func (c *ChainCredentialsProvider) Name() string { return "chain" }
+
+func (c *ChainCredentialsProvider) Retrieve() (*Credentials, error) {
+	var lastErr error
+	for _, p := range c.Providers {
+		creds, err := p.Retrieve()
+		if err == nil {
+			return creds, nil
+		}
+		lastErr = fmt.Errorf("%s: %w", p.Name(), err)
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("connectivity: no credentials provider configured")
+	}
+	return nil, fmt.Errorf("connectivity: credentials chain exhausted: %w", lastErr)
+}
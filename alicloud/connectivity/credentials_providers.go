@@ -0,0 +1,431 @@
+package connectivity
+
+import (
+	"crypto/hmac"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// metadataHTTPGet abstracts the ECS/ECI metadata-service HTTP call so tests
+// can substitute a fake responder instead of hitting the real link-local
+// endpoint.
+type metadataHTTPGet func(url string) (*http.Response, error)
+
+const (
+	ecsRAMRoleMetadataEndpoint = "http://100.100.100.200/latest/meta-data/ram/security-credentials/"
+	eciRAMRoleMetadataEndpoint = "http://100.100.100.200/latest/meta-data/ram/security-credentials/"
+	stsEndpoint                = "https://sts.aliyuncs.com/"
+)
+
+// metadataRoleCredentials is the JSON document the ECS/ECI metadata service
+// returns for a role's security credentials.
+type metadataRoleCredentials struct {
+	Code            string
+	AccessKeyId     string
+	AccessKeySecret string
+	SecurityToken   string
+	Expiration      string
+}
+
+func fetchMetadataRoleCredentials(get metadataHTTPGet, metadataURL string) (*Credentials, error) {
+	resp, err := get(metadataURL)
+	if err != nil {
+		return nil, fmt.Errorf("connectivity: metadata service request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("connectivity: metadata service returned status %d", resp.StatusCode)
+	}
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("connectivity: failed reading metadata service response: %w", err)
+	}
+	var doc metadataRoleCredentials
+	if err := json.Unmarshal(body, &doc); err != nil {
+		return nil, fmt.Errorf("connectivity: failed parsing metadata service response: %w", err)
+	}
+	if doc.Code != "" && doc.Code != "Success" {
+		return nil, fmt.Errorf("connectivity: metadata service returned code %q", doc.Code)
+	}
+	creds := &Credentials{
+		AccessKeyId:     doc.AccessKeyId,
+		AccessKeySecret: doc.AccessKeySecret,
+		SecurityToken:   doc.SecurityToken,
+	}
+	if doc.Expiration != "" {
+		if exp, err := time.Parse(time.RFC3339, doc.Expiration); err == nil {
+			creds.Expiration = exp
+		}
+	}
+	return creds, nil
+}
+
+// NewEcsRamRoleCredentialsProvider resolves credentials from the ECS
+// instance metadata service for roleName, the mechanism ECS instances with
+// an attached RAM role use instead of a static AK/SK. Pass an empty
+// roleName to have it discovered from the metadata service's role-name
+// listing on every refresh.
+func NewEcsRamRoleCredentialsProvider(roleName string) *RefreshableCredentialsProvider {
+	return newEcsRamRoleCredentialsProvider(roleName, http.Get)
+}
+
+func newEcsRamRoleCredentialsProvider(roleName string, get metadataHTTPGet) *RefreshableCredentialsProvider {
+	refresh := func() (*Credentials, error) {
+		name := roleName
+		if name == "" {
+			resp, err := get(ecsRAMRoleMetadataEndpoint)
+			if err != nil {
+				return nil, fmt.Errorf("connectivity: failed discovering the ECS instance RAM role: %w", err)
+			}
+			defer resp.Body.Close()
+			body, err := ioutil.ReadAll(resp.Body)
+			if err != nil {
+				return nil, fmt.Errorf("connectivity: failed reading the ECS instance RAM role name: %w", err)
+			}
+			name = strings.TrimSpace(string(body))
+			if name == "" {
+				return nil, fmt.Errorf("connectivity: instance has no RAM role attached")
+			}
+		}
+		return fetchMetadataRoleCredentials(get, ecsRAMRoleMetadataEndpoint+name)
+	}
+	return NewRefreshableCredentialsProvider("ecs_ram_role", refresh, 5*time.Minute, 5*time.Minute)
+}
+
+// NewEciRamRoleCredentialsProvider resolves credentials from the ECI pod
+// metadata service, the equivalent of NewEcsRamRoleCredentialsProvider for
+// Elastic Container Instance workloads.
+func NewEciRamRoleCredentialsProvider(roleName string) *RefreshableCredentialsProvider {
+	return newEciRamRoleCredentialsProvider(roleName, http.Get)
+}
+
+func newEciRamRoleCredentialsProvider(roleName string, get metadataHTTPGet) *RefreshableCredentialsProvider {
+	refresh := func() (*Credentials, error) {
+		return fetchMetadataRoleCredentials(get, eciRAMRoleMetadataEndpoint+roleName)
+	}
+	return NewRefreshableCredentialsProvider("eci_ram_role", refresh, 5*time.Minute, 5*time.Minute)
+}
+
+// profileConfig mirrors the subset of `~/.aliyun/config.json` (the Alibaba
+// Cloud CLI's profile store) this provider needs: the active profile name
+// and each profile's AK/SK pair.
+type profileConfig struct {
+	Current  string `json:"current"`
+	Profiles []struct {
+		Name            string `json:"name"`
+		Mode            string `json:"mode"`
+		AccessKeyId     string `json:"access_key_id"`
+		AccessKeySecret string `json:"access_key_secret"`
+		StsToken        string `json:"sts_token"`
+	} `json:"profiles"`
+}
+
+// NewProfileCredentialsProvider reads an AK/SK pair out of the Alibaba Cloud
+// CLI's `~/.aliyun/config.json`, using profileName or, if empty, the file's
+// "current" profile. configPath overrides the default location; pass "" to
+// use the CLI's default (~/.aliyun/config.json, or ALICLOUD_CONFIG_FILE).
+func NewProfileCredentialsProvider(profileName, configPath string) *RefreshableCredentialsProvider {
+	refresh := func() (*Credentials, error) {
+		path := configPath
+		if path == "" {
+			path = os.Getenv("ALICLOUD_CONFIG_FILE")
+		}
+		if path == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil, fmt.Errorf("connectivity: cannot locate home directory for CLI profile: %w", err)
+			}
+			path = filepath.Join(home, ".aliyun", "config.json")
+		}
+		body, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("connectivity: failed reading CLI profile %s: %w", path, err)
+		}
+		var cfg profileConfig
+		if err := json.Unmarshal(body, &cfg); err != nil {
+			return nil, fmt.Errorf("connectivity: failed parsing CLI profile %s: %w", path, err)
+		}
+		name := profileName
+		if name == "" {
+			name = cfg.Current
+		}
+		for _, p := range cfg.Profiles {
+			if p.Name != name {
+				continue
+			}
+			if p.AccessKeyId == "" || p.AccessKeySecret == "" {
+				return nil, fmt.Errorf("connectivity: CLI profile %q has no access_key_id/access_key_secret", name)
+			}
+			return &Credentials{
+				AccessKeyId:     p.AccessKeyId,
+				AccessKeySecret: p.AccessKeySecret,
+				SecurityToken:   p.StsToken,
+			}, nil
+		}
+		return nil, fmt.Errorf("connectivity: CLI profile %q not found in %s", name, path)
+	}
+	return NewRefreshableCredentialsProvider("profile", refresh, 30*time.Minute, 0)
+}
+
+// stsAssumeRoleResponse is the subset of STS's AssumeRole/AssumeRoleWithOIDC
+// JSON response this package needs.
+type stsAssumeRoleResponse struct {
+	Credentials struct {
+		AccessKeyId     string
+		AccessKeySecret string
+		SecurityToken   string
+		Expiration      string
+	}
+}
+
+func parseSTSAssumeRoleResponse(body []byte) (*Credentials, error) {
+	var resp stsAssumeRoleResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("connectivity: failed parsing STS AssumeRole response: %w", err)
+	}
+	if resp.Credentials.AccessKeyId == "" {
+		return nil, fmt.Errorf("connectivity: STS AssumeRole response had no credentials: %s", string(body))
+	}
+	creds := &Credentials{
+		AccessKeyId:     resp.Credentials.AccessKeyId,
+		AccessKeySecret: resp.Credentials.AccessKeySecret,
+		SecurityToken:   resp.Credentials.SecurityToken,
+	}
+	if exp, err := time.Parse(time.RFC3339, resp.Credentials.Expiration); err == nil {
+		creds.Expiration = exp
+	}
+	return creds, nil
+}
+
+// rpcPercentEncode applies Alibaba Cloud RPC API's percent-encoding rules
+// (RFC 3986, with "+" kept as "%20", "*" as "%2A" and "%7E" reverted to "~"),
+// which differ from net/url's QueryEscape.
+func rpcPercentEncode(s string) string {
+	encoded := url.QueryEscape(s)
+	encoded = strings.ReplaceAll(encoded, "+", "%20")
+	encoded = strings.ReplaceAll(encoded, "*", "%2A")
+	encoded = strings.ReplaceAll(encoded, "%7E", "~")
+	return encoded
+}
+
+// rpcCanonicalQueryString sorts params by key and joins them with
+// rpcPercentEncode, the encoding both the signature and the request sent on
+// the wire must agree on -- url.Values.Encode() uses form-encoding ("+" for
+// space) instead and must not be used here.
+func rpcCanonicalQueryString(params map[string]string) string {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		pairs = append(pairs, rpcPercentEncode(k)+"="+rpcPercentEncode(params[k]))
+	}
+	return strings.Join(pairs, "&")
+}
+
+// signSTSRequest signs an RPC-style GET request to the STS endpoint using
+// HMAC-SHA1, the signature scheme STS's AssumeRole/AssumeRoleWithOIDC
+// actions use.
+func signSTSRequest(accessKeySecret string, params map[string]string) string {
+	canonicalQuery := rpcCanonicalQueryString(params)
+	stringToSign := "GET&" + rpcPercentEncode("/") + "&" + rpcPercentEncode(canonicalQuery)
+
+	mac := hmac.New(sha1.New, []byte(accessKeySecret+"&"))
+	mac.Write([]byte(stringToSign))
+	return base64.StdEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// NewSTSAssumeRoleCredentialsProvider assumes roleArn via STS AssumeRole,
+// signing the request with source's credentials (typically a static AK/SK
+// or another non-expiring provider). This backs the provider's
+// `assume_role { role_arn = "..." }` block.
+func NewSTSAssumeRoleCredentialsProvider(source CredentialsProvider, roleArn, roleSessionName string, durationSeconds int) *RefreshableCredentialsProvider {
+	if durationSeconds <= 0 {
+		durationSeconds = 3600
+	}
+	refresh := func() (*Credentials, error) {
+		sourceCreds, err := source.Retrieve()
+		if err != nil {
+			return nil, fmt.Errorf("connectivity: sts_assume_role: resolving source credentials: %w", err)
+		}
+
+		params := map[string]string{
+			"Action":           "AssumeRole",
+			"Version":          "2015-04-01",
+			"Format":           "JSON",
+			"SignatureMethod":  "HMAC-SHA1",
+			"SignatureVersion": "1.0",
+			"SignatureNonce":   fmt.Sprintf("%d", time.Now().UnixNano()),
+			"Timestamp":        time.Now().UTC().Format("2006-01-02T15:04:05Z"),
+			"AccessKeyId":      sourceCreds.AccessKeyId,
+			"RoleArn":          roleArn,
+			"RoleSessionName":  roleSessionName,
+			"DurationSeconds":  strconv.Itoa(durationSeconds),
+		}
+		if sourceCreds.SecurityToken != "" {
+			params["SecurityToken"] = sourceCreds.SecurityToken
+		}
+		params["Signature"] = signSTSRequest(sourceCreds.AccessKeySecret, params)
+
+		resp, err := http.Get(stsEndpoint + "?" + rpcCanonicalQueryString(params))
+		if err != nil {
+			return nil, fmt.Errorf("connectivity: sts_assume_role: AssumeRole request failed: %w", err)
+		}
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("connectivity: sts_assume_role: reading AssumeRole response: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("connectivity: sts_assume_role: AssumeRole returned status %d: %s", resp.StatusCode, string(body))
+		}
+		return parseSTSAssumeRoleResponse(body)
+	}
+	return NewRefreshableCredentialsProvider("ram_role_arn", refresh, 5*time.Minute, 5*time.Minute)
+}
+
+// NewOIDCRoleArnCredentialsProvider assumes roleArn via STS
+// AssumeRoleWithOIDC, exchanging the JWT at tokenFilePath (the projected
+// service-account token path ACK/EKS workloads mount, conventionally
+// ALICLOUD_OIDC_TOKEN_FILE) for temporary credentials scoped to
+// oidcProviderArn. Unlike AssumeRole, this action authenticates with the
+// OIDC token itself, so no signing AK/SK is required.
+func NewOIDCRoleArnCredentialsProvider(roleArn, oidcProviderArn, tokenFilePath, roleSessionName string, durationSeconds int) *RefreshableCredentialsProvider {
+	if durationSeconds <= 0 {
+		durationSeconds = 3600
+	}
+	refresh := func() (*Credentials, error) {
+		token, err := ioutil.ReadFile(tokenFilePath)
+		if err != nil {
+			return nil, fmt.Errorf("connectivity: oidc_role_arn: failed reading OIDC token file %s: %w", tokenFilePath, err)
+		}
+
+		query := url.Values{}
+		query.Set("Action", "AssumeRoleWithOIDC")
+		query.Set("Version", "2015-04-01")
+		query.Set("Format", "JSON")
+		query.Set("RoleArn", roleArn)
+		query.Set("OIDCProviderArn", oidcProviderArn)
+		query.Set("OIDCToken", strings.TrimSpace(string(token)))
+		query.Set("RoleSessionName", roleSessionName)
+		query.Set("DurationSeconds", strconv.Itoa(durationSeconds))
+
+		resp, err := http.Get(stsEndpoint + "?" + query.Encode())
+		if err != nil {
+			return nil, fmt.Errorf("connectivity: oidc_role_arn: AssumeRoleWithOIDC request failed: %w", err)
+		}
+		defer resp.Body.Close()
+		body, err := ioutil.ReadAll(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("connectivity: oidc_role_arn: reading AssumeRoleWithOIDC response: %w", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("connectivity: oidc_role_arn: AssumeRoleWithOIDC returned status %d: %s", resp.StatusCode, string(body))
+		}
+		return parseSTSAssumeRoleResponse(body)
+	}
+	return NewRefreshableCredentialsProvider("oidc_role_arn", refresh, 5*time.Minute, 5*time.Minute)
+}
+
+// CredentialsConfig is the resolved form of the provider's `credentials { }`
+// / `assume_role { }` schema blocks. It is the seam a future provider.go's
+// Provider() schema and AliyunClient construction are expected to populate
+// and call NewCredentialsProviderFromConfig with; neither exists in this
+// package snapshot, so this package stops short of wiring itself into them.
+type CredentialsConfig struct {
+	// Provider selects the backing mechanism: "static_ak", "env",
+	// "ecs_ram_role", "eci_ram_role", "ram_role_arn", "oidc_role_arn",
+	// "profile", or "chain" (try EnvLookup, then the instance/pod metadata
+	// service, in order).
+	Provider string
+
+	AccessKeyId     string
+	AccessKeySecret string
+	SecurityToken   string
+
+	EnvLookup func(key string) (string, bool)
+
+	EcsRoleName string
+
+	RoleArn         string
+	RoleSessionName string
+	DurationSeconds int
+	// AssumeRoleSource, when set, is the provider RoleArn is assumed from;
+	// defaults to a ChainCredentialsProvider of env + ecs_ram_role.
+	AssumeRoleSource CredentialsProvider
+
+	OIDCProviderArn string
+	OIDCTokenFile   string
+
+	Profile           string
+	ProfileConfigPath string
+}
+
+// NewCredentialsProviderFromConfig builds the CredentialsProvider described
+// by cfg. It is the single call site through which every concrete provider
+// in this file is expected to be reached.
+func NewCredentialsProviderFromConfig(cfg CredentialsConfig) (CredentialsProvider, error) {
+	switch cfg.Provider {
+	case "", "static_ak":
+		return &StaticCredentialsProvider{
+			AccessKeyId:     cfg.AccessKeyId,
+			AccessKeySecret: cfg.AccessKeySecret,
+			SecurityToken:   cfg.SecurityToken,
+		}, nil
+	case "env":
+		lookup := cfg.EnvLookup
+		if lookup == nil {
+			lookup = func(key string) (string, bool) { return os.LookupEnv(key) }
+		}
+		return &EnvCredentialsProvider{Lookup: lookup}, nil
+	case "ecs_ram_role":
+		return NewEcsRamRoleCredentialsProvider(cfg.EcsRoleName), nil
+	case "eci_ram_role":
+		return NewEciRamRoleCredentialsProvider(cfg.EcsRoleName), nil
+	case "profile":
+		return NewProfileCredentialsProvider(cfg.Profile, cfg.ProfileConfigPath), nil
+	case "ram_role_arn":
+		source := cfg.AssumeRoleSource
+		if source == nil {
+			source = &ChainCredentialsProvider{Providers: []CredentialsProvider{
+				&EnvCredentialsProvider{Lookup: func(key string) (string, bool) { return os.LookupEnv(key) }},
+				NewEcsRamRoleCredentialsProvider(cfg.EcsRoleName),
+			}}
+		}
+		if cfg.RoleArn == "" {
+			return nil, fmt.Errorf("connectivity: ram_role_arn provider requires role_arn")
+		}
+		return NewSTSAssumeRoleCredentialsProvider(source, cfg.RoleArn, cfg.RoleSessionName, cfg.DurationSeconds), nil
+	case "oidc_role_arn":
+		if cfg.RoleArn == "" || cfg.OIDCProviderArn == "" || cfg.OIDCTokenFile == "" {
+			return nil, fmt.Errorf("connectivity: oidc_role_arn provider requires role_arn, oidc_provider_arn and oidc_token_file")
+		}
+		return NewOIDCRoleArnCredentialsProvider(cfg.RoleArn, cfg.OIDCProviderArn, cfg.OIDCTokenFile, cfg.RoleSessionName, cfg.DurationSeconds), nil
+	case "chain":
+		lookup := cfg.EnvLookup
+		if lookup == nil {
+			lookup = func(key string) (string, bool) { return os.LookupEnv(key) }
+		}
+		return &ChainCredentialsProvider{Providers: []CredentialsProvider{
+			&EnvCredentialsProvider{Lookup: lookup},
+			NewEcsRamRoleCredentialsProvider(cfg.EcsRoleName),
+			NewEciRamRoleCredentialsProvider(cfg.EcsRoleName),
+		}}, nil
+	default:
+		return nil, fmt.Errorf("connectivity: unknown credentials provider %q", cfg.Provider)
+	}
+}
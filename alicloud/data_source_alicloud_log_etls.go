@@ -0,0 +1,124 @@
+package alicloud
+
+import (
+	"fmt"
+	"time"
+
+	sls "github.com/aliyun/aliyun-log-go-sdk"
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+// dataSourceAlicloudLogEtls pages through ListETL with server-side
+// offset/size and returns every job in the project. Each job carries its id,
+// name, display_name, logstore, script and status (as desired_state); its
+// description, from_time/to_time, parameters and sinks are not returned.
+func dataSourceAlicloudLogEtls() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAlicloudLogEtlsRead,
+		Schema: map[string]*schema.Schema{
+			"project_name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"output_file": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"names": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"jobs": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id":            {Type: schema.TypeString, Computed: true},
+						"name":          {Type: schema.TypeString, Computed: true},
+						"display_name":  {Type: schema.TypeString, Computed: true},
+						"logstore":      {Type: schema.TypeString, Computed: true},
+						"script":        {Type: schema.TypeString, Computed: true},
+						"desired_state": {Type: schema.TypeString, Computed: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAlicloudLogEtlsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	conn, err := client.NewSlsClient()
+	if err != nil {
+		return WrapError(err)
+	}
+	project := d.Get("project_name").(string)
+
+	var allJobs []*sls.ETL
+	offset, size := 0, PageSizeLarge
+	for {
+		var resp *sls.ListETLResponse
+		err := resource.Retry(5*time.Minute, func() *resource.RetryError {
+			var e error
+			resp, e = conn.ListETL(project, offset, size)
+			if e != nil {
+				if NeedRetry(e) {
+					return resource.RetryableError(e)
+				}
+				return resource.NonRetryableError(e)
+			}
+			return nil
+		})
+		if err != nil {
+			return WrapErrorf(err, DataDefaultErrorMsg, "alicloud_log_etls", "ListETL", AlibabaCloudSdkGoERROR)
+		}
+		if resp == nil || len(resp.Results) == 0 {
+			break
+		}
+		allJobs = append(allJobs, resp.Results...)
+		offset += len(resp.Results)
+		if offset >= resp.Total {
+			break
+		}
+	}
+
+	var ids, names []string
+	var jobs []map[string]interface{}
+	for _, job := range allJobs {
+		id := fmt.Sprintf("%s%s%s", project, COLON_SEPARATED, job.Name)
+		mapping := map[string]interface{}{
+			"id":            id,
+			"name":          job.Name,
+			"display_name":  job.DisplayName,
+			"logstore":      job.Configuration.Logstore,
+			"script":        job.Configuration.Script,
+			"desired_state": fmt.Sprint(job.Status),
+		}
+		ids = append(ids, id)
+		names = append(names, job.Name)
+		jobs = append(jobs, mapping)
+	}
+
+	d.SetId(dataResourceIdHash(ids))
+	d.Set("ids", ids)
+	d.Set("names", names)
+	if err := d.Set("jobs", jobs); err != nil {
+		return WrapError(err)
+	}
+
+	if output, ok := d.GetOk("output_file"); ok && output.(string) != "" {
+		if err := writeToFile(output.(string), jobs); err != nil {
+			return WrapError(err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,137 @@
+package alicloud
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	sls "github.com/aliyun/aliyun-log-go-sdk"
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+)
+
+func dataSourceAlicloudLogScheduledSqls() *schema.Resource {
+	return &schema.Resource{
+		Read: dataSourceAlicloudLogScheduledSqlsRead,
+		Schema: map[string]*schema.Schema{
+			"project_name": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"name_regex": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"output_file": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"ids": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"names": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"sqls": {
+				Type:     schema.TypeList,
+				Computed: true,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id":            {Type: schema.TypeString, Computed: true},
+						"name":          {Type: schema.TypeString, Computed: true},
+						"display_name":  {Type: schema.TypeString, Computed: true},
+						"script":        {Type: schema.TypeString, Computed: true},
+						"src_logstore":  {Type: schema.TypeString, Computed: true},
+						"dest_logstore": {Type: schema.TypeString, Computed: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceAlicloudLogScheduledSqlsRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	conn, err := client.NewSlsClient()
+	if err != nil {
+		return WrapError(err)
+	}
+	project := d.Get("project_name").(string)
+
+	var nameRegex *regexp.Regexp
+	if v, ok := d.GetOk("name_regex"); ok {
+		r, err := regexp.Compile(v.(string))
+		if err != nil {
+			return WrapError(err)
+		}
+		nameRegex = r
+	}
+
+	var allJobs []*sls.ScheduledSQL
+	offset, size := 0, PageSizeLarge
+	for {
+		var jobs []*sls.ScheduledSQL
+		var total int
+		err := resource.Retry(5*time.Minute, func() *resource.RetryError {
+			var e error
+			jobs, total, _, e = conn.ListScheduledSQL(project, "", "", offset, size)
+			if e != nil {
+				if NeedRetry(e) {
+					return resource.RetryableError(e)
+				}
+				return resource.NonRetryableError(e)
+			}
+			return nil
+		})
+		if err != nil {
+			return WrapErrorf(err, DataDefaultErrorMsg, "alicloud_log_scheduled_sqls", "ListScheduledSQL", AlibabaCloudSdkGoERROR)
+		}
+		allJobs = append(allJobs, jobs...)
+		offset += len(jobs)
+		if len(jobs) == 0 || offset >= total {
+			break
+		}
+	}
+
+	var ids, names []string
+	var sqls []map[string]interface{}
+	for _, job := range allJobs {
+		if nameRegex != nil && !nameRegex.MatchString(job.Name) {
+			continue
+		}
+		id := fmt.Sprintf("%s%s%s", project, COLON_SEPARATED, job.Name)
+		mapping := map[string]interface{}{
+			"id":           id,
+			"name":         job.Name,
+			"display_name": job.DisplayName,
+		}
+		if job.Configuration != nil {
+			mapping["script"] = job.Configuration.Script
+			mapping["src_logstore"] = job.Configuration.SourceLogstore
+			mapping["dest_logstore"] = job.Configuration.DestLogstore
+		}
+		ids = append(ids, id)
+		names = append(names, job.Name)
+		sqls = append(sqls, mapping)
+	}
+
+	d.SetId(dataResourceIdHash(ids))
+	d.Set("ids", ids)
+	d.Set("names", names)
+	if err := d.Set("sqls", sqls); err != nil {
+		return WrapError(err)
+	}
+
+	if output, ok := d.GetOk("output_file"); ok && output.(string) != "" {
+		if err := writeToFile(output.(string), sqls); err != nil {
+			return WrapError(err)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package alicloud
+
+import (
+	"testing"
+
+	util "github.com/alibabacloud-go/tea-utils/service"
+)
+
+// fakePaginatedListClient replays a fixed sequence of responses, one per
+// DoRequest call, so tests can drive PaginatedList through specific
+// pagination shapes without a real RPC client.
+type fakePaginatedListClient struct {
+	pages []map[string]interface{}
+	calls int
+}
+
+func (f *fakePaginatedListClient) DoRequest(action *string, product *string, method *string, version *string, authType *string,
+	request map[string]interface{}, body map[string]interface{}, runtime *util.RuntimeOptions) (map[string]interface{}, error) {
+	page := f.pages[f.calls]
+	if f.calls < len(f.pages)-1 {
+		f.calls++
+	}
+	return page, nil
+}
+
+func TestPaginatedList_EmptyPage(t *testing.T) {
+	conn := &fakePaginatedListClient{pages: []map[string]interface{}{
+		{"Data": map[string]interface{}{"VirtualHosts": []interface{}{}}},
+	}}
+	items, _, err := PaginatedList(PaginatedListRequest{Conn: conn, Action: "List", Method: "GET", Version: "v1", AuthType: "AK",
+		Request: map[string]interface{}{}, DataPath: "$.Data.VirtualHosts"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 0 {
+		t.Fatalf("expected no items on an empty page, got %d", len(items))
+	}
+}
+
+func TestPaginatedList_MissingNextToken(t *testing.T) {
+	conn := &fakePaginatedListClient{pages: []map[string]interface{}{
+		{"Data": map[string]interface{}{"VirtualHosts": []interface{}{
+			map[string]interface{}{"Name": "a"},
+		}}},
+	}}
+	items, _, err := PaginatedList(PaginatedListRequest{Conn: conn, Action: "List", Method: "GET", Version: "v1", AuthType: "AK",
+		Request: map[string]interface{}{}, DataPath: "$.Data.VirtualHosts"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 1 {
+		t.Fatalf("expected pagination to stop after one page with no NextToken, got %d items", len(items))
+	}
+}
+
+func TestPaginatedList_TokenRepeatsPreviousValue(t *testing.T) {
+	conn := &fakePaginatedListClient{pages: []map[string]interface{}{
+		{
+			"NextToken": "same-token",
+			"Data":      map[string]interface{}{"VirtualHosts": []interface{}{map[string]interface{}{"Name": "a"}}},
+		},
+		{
+			"NextToken": "same-token",
+			"Data":      map[string]interface{}{"VirtualHosts": []interface{}{map[string]interface{}{"Name": "b"}}},
+		},
+	}}
+	items, _, err := PaginatedList(PaginatedListRequest{Conn: conn, Action: "List", Method: "GET", Version: "v1", AuthType: "AK",
+		Request: map[string]interface{}{}, DataPath: "$.Data.VirtualHosts"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// A NextToken identical to the one we just used must not be followed
+	// again, or pagination would loop forever; the page fetched with that
+	// token is still collected, so both pages' items come back.
+	if len(items) != 2 {
+		t.Fatalf("expected pagination to terminate on a repeated NextToken, got %d items", len(items))
+	}
+}
+
+func TestPaginatedList_AdvancesAcrossMultiplePages(t *testing.T) {
+	conn := &fakePaginatedListClient{pages: []map[string]interface{}{
+		{
+			"NextToken": "token-1",
+			"Data":      map[string]interface{}{"VirtualHosts": []interface{}{map[string]interface{}{"Name": "a"}}},
+		},
+		{
+			"Data": map[string]interface{}{"VirtualHosts": []interface{}{map[string]interface{}{"Name": "b"}}},
+		},
+	}}
+	items, _, err := PaginatedList(PaginatedListRequest{Conn: conn, Action: "List", Method: "GET", Version: "v1", AuthType: "AK",
+		Request: map[string]interface{}{}, DataPath: "$.Data.VirtualHosts"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("expected items from both pages, got %d", len(items))
+	}
+}
+
+func TestPaginatedList_MatchStopsEarly(t *testing.T) {
+	conn := &fakePaginatedListClient{pages: []map[string]interface{}{
+		{
+			"NextToken": "token-1",
+			"Data":      map[string]interface{}{"VirtualHosts": []interface{}{map[string]interface{}{"Name": "a"}}},
+		},
+		{
+			"Data": map[string]interface{}{"VirtualHosts": []interface{}{map[string]interface{}{"Name": "b"}}},
+		},
+	}}
+	items, _, err := PaginatedList(PaginatedListRequest{Conn: conn, Action: "List", Method: "GET", Version: "v1", AuthType: "AK",
+		Request: map[string]interface{}{}, DataPath: "$.Data.VirtualHosts",
+		Match: func(item map[string]interface{}) bool { return item["Name"] == "a" },
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 1 || items[0].(map[string]interface{})["Name"] != "a" {
+		t.Fatalf("expected Match to return only the matching item from the first page, got %v", items)
+	}
+	if conn.calls != 1 {
+		t.Fatalf("expected Match to short-circuit after the first page, got %d calls", conn.calls)
+	}
+}
+
+func TestPaginatedList_SurfacesDataPathError(t *testing.T) {
+	conn := &fakePaginatedListClient{pages: []map[string]interface{}{
+		{"Data": map[string]interface{}{}},
+	}}
+	_, _, err := PaginatedList(PaginatedListRequest{Conn: conn, Action: "List", Method: "GET", Version: "v1", AuthType: "AK",
+		Request: map[string]interface{}{}, DataPath: "$.Data.VirtualHosts"})
+	if err == nil {
+		t.Fatal("expected an error when DataPath does not resolve against the response")
+	}
+}
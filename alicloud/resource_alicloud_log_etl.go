@@ -0,0 +1,347 @@
+package alicloud
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	sls "github.com/aliyun/aliyun-log-go-sdk"
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func resourceAlicloudLogEtl() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAlicloudLogEtlCreate,
+		Read:   resourceAlicloudLogEtlRead,
+		Update: resourceAlicloudLogEtlUpdate,
+		Delete: resourceAlicloudLogEtlDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceAlicloudLogEtlImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"project_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"logstore": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"display_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"script": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"from_time": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+			"to_time": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  0,
+			},
+			"parameters": {
+				Type:     schema.TypeMap,
+				Optional: true,
+				Elem:     &schema.Schema{Type: schema.TypeString},
+			},
+			"sinks": {
+				Type:     schema.TypeList,
+				Required: true,
+				MinItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"name": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"project_name": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"logstore": {
+							Type:     schema.TypeString,
+							Required: true,
+						},
+						"role_arn": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"endpoint": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+					},
+				},
+			},
+			"desired_state": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "RUNNING",
+				ValidateFunc: validation.StringInSlice([]string{"RUNNING", "STOPPED"}, false),
+			},
+		},
+	}
+}
+
+func buildEtlFromResourceData(d *schema.ResourceData) *sls.ETL {
+	etl := &sls.ETL{
+		Name:        d.Get("name").(string),
+		DisplayName: d.Get("display_name").(string),
+		Description: d.Get("description").(string),
+		Configuration: sls.ETLConfiguration{
+			Logstore: d.Get("logstore").(string),
+			Script:   d.Get("script").(string),
+			FromTime: int64(d.Get("from_time").(int)),
+			ToTime:   int64(d.Get("to_time").(int)),
+		},
+	}
+
+	if v, ok := d.GetOk("parameters"); ok {
+		params := make(map[string]string)
+		for k, v := range v.(map[string]interface{}) {
+			params[k] = v.(string)
+		}
+		etl.Configuration.Parameters = params
+	}
+
+	var sinks []*sls.ETLSink
+	for _, raw := range d.Get("sinks").([]interface{}) {
+		m := raw.(map[string]interface{})
+		sinks = append(sinks, &sls.ETLSink{
+			Name:        m["name"].(string),
+			ProjectName: m["project_name"].(string),
+			Logstore:    m["logstore"].(string),
+			RoleArn:     m["role_arn"].(string),
+			Endpoint:    m["endpoint"].(string),
+		})
+	}
+	etl.Configuration.Sinks = sinks
+
+	return etl
+}
+
+func resourceAlicloudLogEtlCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	project := d.Get("project_name").(string)
+
+	etl := buildEtlFromResourceData(d)
+
+	conn, err := client.NewSlsClient()
+	if err != nil {
+		return WrapError(err)
+	}
+	if err := resource.Retry(5*time.Minute, func() *resource.RetryError {
+		if err := conn.CreateETL(project, *etl); err != nil {
+			if NeedRetry(err) {
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	}); err != nil {
+		return WrapErrorf(err, DefaultErrorMsg, "alicloud_log_etl", "CreateETL", AlibabaCloudSdkGoERROR)
+	}
+	addDebug("CreateETL", etl, map[string]interface{}{"project": project})
+
+	d.SetId(fmt.Sprintf("%s%s%s", project, COLON_SEPARATED, etl.Name))
+
+	if d.Get("desired_state").(string) == "RUNNING" {
+		if err := resource.Retry(5*time.Minute, func() *resource.RetryError {
+			if err := conn.StartETL(project, etl.Name); err != nil {
+				if NeedRetry(err) {
+					return resource.RetryableError(err)
+				}
+				return resource.NonRetryableError(err)
+			}
+			return nil
+		}); err != nil {
+			return WrapErrorf(err, DefaultErrorMsg, d.Id(), "StartETL", AlibabaCloudSdkGoERROR)
+		}
+	}
+
+	return resourceAlicloudLogEtlRead(d, meta)
+}
+
+func resourceAlicloudLogEtlRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	logService := LogService{client}
+
+	object, err := logService.DescribeLogEtl(d.Id())
+	if err != nil {
+		if NotFoundError(err) {
+			log.Printf("[DEBUG] Resource alicloud_log_etl logService.DescribeLogEtl Failed!!! %s", err)
+			d.SetId("")
+			return nil
+		}
+		return WrapError(err)
+	}
+
+	parts, err := ParseResourceId(d.Id(), 2)
+	if err != nil {
+		return WrapError(err)
+	}
+	d.Set("project_name", parts[0])
+	d.Set("name", object.Name)
+	d.Set("display_name", object.DisplayName)
+	d.Set("description", object.Description)
+	d.Set("logstore", object.Configuration.Logstore)
+	d.Set("script", object.Configuration.Script)
+	d.Set("from_time", int(object.Configuration.FromTime))
+	d.Set("to_time", int(object.Configuration.ToTime))
+	d.Set("parameters", object.Configuration.Parameters)
+
+	var sinks []map[string]interface{}
+	for _, sink := range object.Configuration.Sinks {
+		sinks = append(sinks, map[string]interface{}{
+			"name":         sink.Name,
+			"project_name": sink.ProjectName,
+			"logstore":     sink.Logstore,
+			"role_arn":     sink.RoleArn,
+			"endpoint":     sink.Endpoint,
+		})
+	}
+	d.Set("sinks", sinks)
+	d.Set("desired_state", fmt.Sprint(object.Status))
+
+	return nil
+}
+
+// resourceAlicloudLogEtlUpdate restarts a running job with RestartETL when
+// only the script or parameters changed, since that is cheaper than a full
+// UpdateETL + Stop/Start cycle; any other field change goes through
+// UpdateETL. desired_state transitions are handled separately via
+// StartETL/StopETL.
+func resourceAlicloudLogEtlUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	parts, err := ParseResourceId(d.Id(), 2)
+	if err != nil {
+		return WrapError(err)
+	}
+	project, name := parts[0], parts[1]
+
+	conn, err := client.NewSlsClient()
+	if err != nil {
+		return WrapError(err)
+	}
+
+	onlyScriptOrParams := (d.HasChange("script") || d.HasChange("parameters")) &&
+		!d.HasChange("logstore") && !d.HasChange("sinks") && !d.HasChange("from_time") && !d.HasChange("to_time") &&
+		!d.HasChange("display_name") && !d.HasChange("description")
+
+	if d.HasChange("script") || d.HasChange("parameters") || d.HasChange("logstore") ||
+		d.HasChange("sinks") || d.HasChange("from_time") || d.HasChange("to_time") ||
+		d.HasChange("display_name") || d.HasChange("description") {
+		etl := buildEtlFromResourceData(d)
+
+		currentState := "STOPPED"
+		if object, err := (&LogService{client}).DescribeLogEtl(d.Id()); err == nil {
+			currentState = fmt.Sprint(object.Status)
+		}
+
+		if onlyScriptOrParams && currentState == "RUNNING" {
+			if err := resource.Retry(5*time.Minute, func() *resource.RetryError {
+				if err := conn.RestartETL(project, *etl); err != nil {
+					if NeedRetry(err) {
+						return resource.RetryableError(err)
+					}
+					return resource.NonRetryableError(err)
+				}
+				return nil
+			}); err != nil {
+				return WrapErrorf(err, DefaultErrorMsg, d.Id(), "RestartETL", AlibabaCloudSdkGoERROR)
+			}
+			addDebug("RestartETL", etl, map[string]interface{}{"project": project})
+		} else {
+			if err := resource.Retry(5*time.Minute, func() *resource.RetryError {
+				if err := conn.UpdateETL(project, *etl); err != nil {
+					if NeedRetry(err) {
+						return resource.RetryableError(err)
+					}
+					return resource.NonRetryableError(err)
+				}
+				return nil
+			}); err != nil {
+				return WrapErrorf(err, DefaultErrorMsg, d.Id(), "UpdateETL", AlibabaCloudSdkGoERROR)
+			}
+			addDebug("UpdateETL", etl, map[string]interface{}{"project": project})
+		}
+	}
+
+	if d.HasChange("desired_state") {
+		switch d.Get("desired_state").(string) {
+		case "RUNNING":
+			if err := conn.StartETL(project, name); err != nil {
+				return WrapErrorf(err, DefaultErrorMsg, d.Id(), "StartETL", AlibabaCloudSdkGoERROR)
+			}
+		case "STOPPED":
+			if err := conn.StopETL(project, name); err != nil {
+				return WrapErrorf(err, DefaultErrorMsg, d.Id(), "StopETL", AlibabaCloudSdkGoERROR)
+			}
+		}
+	}
+
+	return resourceAlicloudLogEtlRead(d, meta)
+}
+
+func resourceAlicloudLogEtlDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	parts, err := ParseResourceId(d.Id(), 2)
+	if err != nil {
+		return WrapError(err)
+	}
+	project, name := parts[0], parts[1]
+
+	conn, err := client.NewSlsClient()
+	if err != nil {
+		return WrapError(err)
+	}
+	if err := resource.Retry(5*time.Minute, func() *resource.RetryError {
+		if err := conn.DeleteETL(project, name); err != nil {
+			if NeedRetry(err) {
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	}); err != nil {
+		if IsExpectedErrors(err, []string{"ETLNotExist", "JobNotExist"}) {
+			return nil
+		}
+		return WrapErrorf(err, DefaultErrorMsg, d.Id(), "DeleteETL", AlibabaCloudSdkGoERROR)
+	}
+	return nil
+}
+
+func resourceAlicloudLogEtlImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts, err := ParseResourceId(d.Id(), 2)
+	if err != nil {
+		return nil, WrapError(err)
+	}
+	d.Set("project_name", parts[0])
+	d.Set("name", parts[1])
+	return []*schema.ResourceData{d}, nil
+}
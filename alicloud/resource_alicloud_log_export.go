@@ -0,0 +1,353 @@
+package alicloud
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	sls "github.com/aliyun/aliyun-log-go-sdk"
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+// resourceAlicloudLogExport's mutable fields (from_time, filter and sink
+// credentials) are applied through RestartExport so an `apply` cycle
+// restarts the export job without forcing a replace; metadata-only changes
+// use UpdateExport instead. See resourceAlicloudLogExportUpdate.
+func resourceAlicloudLogExport() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAlicloudLogExportCreate,
+		Read:   resourceAlicloudLogExportRead,
+		Update: resourceAlicloudLogExportUpdate,
+		Delete: resourceAlicloudLogExportDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceAlicloudLogExportImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"project_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"logstore_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"display_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"from_time": {
+				Type:     schema.TypeInt,
+				Optional: true,
+			},
+			"filter": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"sink_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"OSS", "AnalyticDB", "TSDB"}, false),
+			},
+			"oss_sink": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"bucket":          {Type: schema.TypeString, Required: true},
+						"prefix":          {Type: schema.TypeString, Optional: true},
+						"role_arn":        {Type: schema.TypeString, Required: true},
+						"buffer_interval": {Type: schema.TypeInt, Optional: true, Default: 300},
+						"buffer_size":     {Type: schema.TypeInt, Optional: true, Default: 64},
+						"format_type":     {Type: schema.TypeString, Optional: true, Default: "json"},
+					},
+				},
+			},
+			"analyticdb_sink": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"instance_id": {Type: schema.TypeString, Required: true},
+						"database":    {Type: schema.TypeString, Required: true},
+						"table":       {Type: schema.TypeString, Required: true},
+						"user":        {Type: schema.TypeString, Required: true},
+						"password":    {Type: schema.TypeString, Required: true, Sensitive: true},
+						"role_arn":    {Type: schema.TypeString, Optional: true},
+					},
+				},
+			},
+			"tsdb_sink": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"instance_id": {Type: schema.TypeString, Required: true},
+						"role_arn":    {Type: schema.TypeString, Optional: true},
+					},
+				},
+			},
+		},
+	}
+}
+
+func buildExportFromResourceData(d *schema.ResourceData) (*sls.Export, error) {
+	export := &sls.Export{
+		Name:        d.Get("name").(string),
+		DisplayName: d.Get("display_name").(string),
+		Description: d.Get("description").(string),
+	}
+	conf := &sls.ExportConfiguration{
+		Logstore: d.Get("logstore_name").(string),
+		FromTime: int64(d.Get("from_time").(int)),
+		Filter:   d.Get("filter").(string),
+	}
+	switch sinkType := d.Get("sink_type").(string); sinkType {
+	case "OSS":
+		if v, ok := d.GetOk("oss_sink"); ok && len(v.([]interface{})) > 0 {
+			m := v.([]interface{})[0].(map[string]interface{})
+			conf.Sink = &sls.OSSSink{
+				Bucket:         m["bucket"].(string),
+				Prefix:         m["prefix"].(string),
+				RoleArn:        m["role_arn"].(string),
+				BufferInterval: int32(m["buffer_interval"].(int)),
+				BufferSize:     int32(m["buffer_size"].(int)),
+				FormatType:     m["format_type"].(string),
+			}
+		}
+	case "AnalyticDB":
+		if v, ok := d.GetOk("analyticdb_sink"); ok && len(v.([]interface{})) > 0 {
+			m := v.([]interface{})[0].(map[string]interface{})
+			conf.Sink = &sls.ADBSink{
+				InstanceId: m["instance_id"].(string),
+				Database:   m["database"].(string),
+				Table:      m["table"].(string),
+				User:       m["user"].(string),
+				Password:   m["password"].(string),
+				RoleArn:    m["role_arn"].(string),
+			}
+		}
+	case "TSDB":
+		if v, ok := d.GetOk("tsdb_sink"); ok && len(v.([]interface{})) > 0 {
+			m := v.([]interface{})[0].(map[string]interface{})
+			conf.Sink = &sls.TSDBSink{
+				InstanceId: m["instance_id"].(string),
+				RoleArn:    m["role_arn"].(string),
+			}
+		}
+	default:
+		return nil, WrapError(fmt.Errorf("unsupported export sink_type: %s", sinkType))
+	}
+	export.Configuration = conf
+	return export, nil
+}
+
+func resourceAlicloudLogExportCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	project := d.Get("project_name").(string)
+
+	export, err := buildExportFromResourceData(d)
+	if err != nil {
+		return WrapError(err)
+	}
+
+	conn, err := client.NewSlsClient()
+	if err != nil {
+		return WrapError(err)
+	}
+	if err := resource.Retry(5*time.Minute, func() *resource.RetryError {
+		if err := conn.CreateExport(project, export); err != nil {
+			if NeedRetry(err) {
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	}); err != nil {
+		return WrapErrorf(err, DefaultErrorMsg, "alicloud_log_export", "CreateExport", AlibabaCloudSdkGoERROR)
+	}
+	addDebug("CreateExport", export, map[string]interface{}{"project": project})
+
+	d.SetId(fmt.Sprintf("%s%s%s", project, COLON_SEPARATED, export.Name))
+	return resourceAlicloudLogExportRead(d, meta)
+}
+
+func resourceAlicloudLogExportRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	logService := LogService{client}
+
+	object, err := logService.DescribeLogExport(d.Id())
+	if err != nil {
+		if NotFoundError(err) {
+			log.Printf("[DEBUG] Resource alicloud_log_export logService.DescribeLogExport Failed!!! %s", err)
+			d.SetId("")
+			return nil
+		}
+		return WrapError(err)
+	}
+
+	parts, err := ParseResourceId(d.Id(), 2)
+	if err != nil {
+		return WrapError(err)
+	}
+	d.Set("project_name", parts[0])
+	d.Set("name", object.Name)
+	d.Set("display_name", object.DisplayName)
+	d.Set("description", object.Description)
+	if c := object.Configuration; c != nil {
+		d.Set("logstore_name", c.Logstore)
+		d.Set("from_time", int(c.FromTime))
+		d.Set("filter", c.Filter)
+		switch sink := c.Sink.(type) {
+		case *sls.OSSSink:
+			d.Set("sink_type", "OSS")
+			d.Set("oss_sink", []map[string]interface{}{{
+				"bucket":          sink.Bucket,
+				"prefix":          sink.Prefix,
+				"role_arn":        sink.RoleArn,
+				"buffer_interval": int(sink.BufferInterval),
+				"buffer_size":     int(sink.BufferSize),
+				"format_type":     sink.FormatType,
+			}})
+		case *sls.ADBSink:
+			d.Set("sink_type", "AnalyticDB")
+			d.Set("analyticdb_sink", []map[string]interface{}{{
+				"instance_id": sink.InstanceId,
+				"database":    sink.Database,
+				"table":       sink.Table,
+				"user":        sink.User,
+				"password":    sink.Password,
+				"role_arn":    sink.RoleArn,
+			}})
+		case *sls.TSDBSink:
+			d.Set("sink_type", "TSDB")
+			d.Set("tsdb_sink", []map[string]interface{}{{
+				"instance_id": sink.InstanceId,
+				"role_arn":    sink.RoleArn,
+			}})
+		}
+	}
+	return nil
+}
+
+// resourceAlicloudLogExportUpdate restarts the export job via RestartExport
+// only when a mutable field that affects the running job (from_time, filter,
+// sink credentials) changed; metadata-only edits (display_name, description)
+// go through UpdateExport so they don't interrupt an in-flight export.
+func resourceAlicloudLogExportUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	parts, err := ParseResourceId(d.Id(), 2)
+	if err != nil {
+		return WrapError(err)
+	}
+	project := parts[0]
+
+	export, err := buildExportFromResourceData(d)
+	if err != nil {
+		return WrapError(err)
+	}
+
+	conn, err := client.NewSlsClient()
+	if err != nil {
+		return WrapError(err)
+	}
+
+	restart := false
+	for _, key := range []string{"from_time", "filter", "oss_sink", "analyticdb_sink", "tsdb_sink"} {
+		if d.HasChange(key) {
+			restart = true
+			break
+		}
+	}
+
+	if restart {
+		if err := resource.Retry(5*time.Minute, func() *resource.RetryError {
+			if err := conn.RestartExport(project, export); err != nil {
+				if NeedRetry(err) {
+					return resource.RetryableError(err)
+				}
+				return resource.NonRetryableError(err)
+			}
+			return nil
+		}); err != nil {
+			return WrapErrorf(err, DefaultErrorMsg, d.Id(), "RestartExport", AlibabaCloudSdkGoERROR)
+		}
+		addDebug("RestartExport", export, map[string]interface{}{"project": project})
+	} else {
+		if err := resource.Retry(5*time.Minute, func() *resource.RetryError {
+			if err := conn.UpdateExport(project, export); err != nil {
+				if NeedRetry(err) {
+					return resource.RetryableError(err)
+				}
+				return resource.NonRetryableError(err)
+			}
+			return nil
+		}); err != nil {
+			return WrapErrorf(err, DefaultErrorMsg, d.Id(), "UpdateExport", AlibabaCloudSdkGoERROR)
+		}
+		addDebug("UpdateExport", export, map[string]interface{}{"project": project})
+	}
+
+	return resourceAlicloudLogExportRead(d, meta)
+}
+
+func resourceAlicloudLogExportDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	parts, err := ParseResourceId(d.Id(), 2)
+	if err != nil {
+		return WrapError(err)
+	}
+	project, name := parts[0], parts[1]
+
+	conn, err := client.NewSlsClient()
+	if err != nil {
+		return WrapError(err)
+	}
+	if err := resource.Retry(5*time.Minute, func() *resource.RetryError {
+		if err := conn.DeleteExport(project, name); err != nil {
+			if NeedRetry(err) {
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	}); err != nil {
+		if IsExpectedErrors(err, []string{"ExportNotExist", "JobNotExist"}) {
+			return nil
+		}
+		return WrapErrorf(err, DefaultErrorMsg, d.Id(), "DeleteExport", AlibabaCloudSdkGoERROR)
+	}
+	return nil
+}
+
+func resourceAlicloudLogExportImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts, err := ParseResourceId(d.Id(), 2)
+	if err != nil {
+		return nil, WrapError(err)
+	}
+	d.Set("project_name", parts[0])
+	d.Set("name", parts[1])
+	return []*schema.ResourceData{d}, nil
+}
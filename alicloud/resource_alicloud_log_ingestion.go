@@ -0,0 +1,329 @@
+package alicloud
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	sls "github.com/aliyun/aliyun-log-go-sdk"
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func resourceAlicloudLogIngestion() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAlicloudLogIngestionCreate,
+		Read:   resourceAlicloudLogIngestionRead,
+		Update: resourceAlicloudLogIngestionUpdate,
+		Delete: resourceAlicloudLogIngestionDelete,
+		Importer: &schema.ResourceImporter{
+			State: resourceAlicloudLogIngestionImport,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"project_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"logstore_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"display_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"source_type": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ForceNew:     true,
+				ValidateFunc: validation.StringInSlice([]string{"OSS", "Kafka", "MySQL_Binlog"}, false),
+			},
+			"oss_source": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"bucket":   {Type: schema.TypeString, Required: true},
+						"prefix":   {Type: schema.TypeString, Optional: true},
+						"role_arn": {Type: schema.TypeString, Required: true},
+						"encoding": {Type: schema.TypeString, Optional: true, Default: "UTF8"},
+						"compress_type": {
+							Type:     schema.TypeString,
+							Optional: true,
+						},
+						"pattern": {Type: schema.TypeString, Optional: true},
+					},
+				},
+			},
+			"kafka_source": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"topics":            {Type: schema.TypeString, Required: true},
+						"bootstrap_servers": {Type: schema.TypeString, Required: true},
+						"consumer_group":    {Type: schema.TypeString, Optional: true},
+						"from_time":         {Type: schema.TypeInt, Optional: true},
+						"security_protocol": {Type: schema.TypeString, Optional: true},
+						"value_encoding":    {Type: schema.TypeString, Optional: true},
+					},
+				},
+			},
+			"mysql_binlog_source": {
+				Type:     schema.TypeList,
+				Optional: true,
+				MaxItems: 1,
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"host":     {Type: schema.TypeString, Required: true},
+						"port":     {Type: schema.TypeInt, Required: true},
+						"user":     {Type: schema.TypeString, Required: true},
+						"password": {Type: schema.TypeString, Required: true, Sensitive: true},
+						"databases": {
+							Type:     schema.TypeList,
+							Optional: true,
+							Elem:     &schema.Schema{Type: schema.TypeString},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func buildIngestionFromResourceData(d *schema.ResourceData) (*sls.Ingestion, error) {
+	ingestion := &sls.Ingestion{
+		Name:        d.Get("name").(string),
+		DisplayName: d.Get("display_name").(string),
+		Description: d.Get("description").(string),
+	}
+	conf := &sls.IngestionConfiguration{
+		Logstore: d.Get("logstore_name").(string),
+	}
+	switch sourceType := d.Get("source_type").(string); sourceType {
+	case "OSS":
+		if v, ok := d.GetOk("oss_source"); ok && len(v.([]interface{})) > 0 {
+			m := v.([]interface{})[0].(map[string]interface{})
+			conf.Source = &sls.OSSSource{
+				Bucket:       m["bucket"].(string),
+				Prefix:       m["prefix"].(string),
+				RoleArn:      m["role_arn"].(string),
+				Encoding:     m["encoding"].(string),
+				CompressType: m["compress_type"].(string),
+				Pattern:      m["pattern"].(string),
+			}
+		}
+	case "Kafka":
+		if v, ok := d.GetOk("kafka_source"); ok && len(v.([]interface{})) > 0 {
+			m := v.([]interface{})[0].(map[string]interface{})
+			conf.Source = &sls.KafkaSource{
+				Topics:           m["topics"].(string),
+				BootstrapServers: m["bootstrap_servers"].(string),
+				ConsumerGroup:    m["consumer_group"].(string),
+				FromTime:         int64(m["from_time"].(int)),
+				SecurityProtocol: m["security_protocol"].(string),
+				ValueEncoding:    m["value_encoding"].(string),
+			}
+		}
+	case "MySQL_Binlog":
+		if v, ok := d.GetOk("mysql_binlog_source"); ok && len(v.([]interface{})) > 0 {
+			m := v.([]interface{})[0].(map[string]interface{})
+			databases := make([]string, 0)
+			for _, db := range m["databases"].([]interface{}) {
+				databases = append(databases, db.(string))
+			}
+			conf.Source = &sls.MySQLBinlogSource{
+				Host:      m["host"].(string),
+				Port:      int32(m["port"].(int)),
+				User:      m["user"].(string),
+				Password:  m["password"].(string),
+				Databases: databases,
+			}
+		}
+	default:
+		return nil, WrapError(fmt.Errorf("unsupported ingestion source_type: %s", sourceType))
+	}
+	ingestion.Configuration = conf
+	return ingestion, nil
+}
+
+func resourceAlicloudLogIngestionCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	project := d.Get("project_name").(string)
+
+	ingestion, err := buildIngestionFromResourceData(d)
+	if err != nil {
+		return WrapError(err)
+	}
+
+	conn, err := client.NewSlsClient()
+	if err != nil {
+		return WrapError(err)
+	}
+	if err := resource.Retry(5*time.Minute, func() *resource.RetryError {
+		if err := conn.CreateIngestion(project, ingestion); err != nil {
+			if NeedRetry(err) {
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	}); err != nil {
+		return WrapErrorf(err, DefaultErrorMsg, "alicloud_log_ingestion", "CreateIngestion", AlibabaCloudSdkGoERROR)
+	}
+	addDebug("CreateIngestion", ingestion, map[string]interface{}{"project": project})
+
+	d.SetId(fmt.Sprintf("%s%s%s", project, COLON_SEPARATED, ingestion.Name))
+	return resourceAlicloudLogIngestionRead(d, meta)
+}
+
+func resourceAlicloudLogIngestionRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	logService := LogService{client}
+
+	object, err := logService.DescribeLogIngestion(d.Id())
+	if err != nil {
+		if NotFoundError(err) {
+			log.Printf("[DEBUG] Resource alicloud_log_ingestion logService.DescribeLogIngestion Failed!!! %s", err)
+			d.SetId("")
+			return nil
+		}
+		return WrapError(err)
+	}
+
+	parts, err := ParseResourceId(d.Id(), 2)
+	if err != nil {
+		return WrapError(err)
+	}
+	d.Set("project_name", parts[0])
+	d.Set("name", object.Name)
+	d.Set("display_name", object.DisplayName)
+	d.Set("description", object.Description)
+	if c := object.Configuration; c != nil {
+		d.Set("logstore_name", c.Logstore)
+		switch source := c.Source.(type) {
+		case *sls.OSSSource:
+			d.Set("source_type", "OSS")
+			d.Set("oss_source", []map[string]interface{}{{
+				"bucket":        source.Bucket,
+				"prefix":        source.Prefix,
+				"role_arn":      source.RoleArn,
+				"encoding":      source.Encoding,
+				"compress_type": source.CompressType,
+				"pattern":       source.Pattern,
+			}})
+		case *sls.KafkaSource:
+			d.Set("source_type", "Kafka")
+			d.Set("kafka_source", []map[string]interface{}{{
+				"topics":            source.Topics,
+				"bootstrap_servers": source.BootstrapServers,
+				"consumer_group":    source.ConsumerGroup,
+				"from_time":         int(source.FromTime),
+				"security_protocol": source.SecurityProtocol,
+				"value_encoding":    source.ValueEncoding,
+			}})
+		case *sls.MySQLBinlogSource:
+			d.Set("source_type", "MySQL_Binlog")
+			d.Set("mysql_binlog_source", []map[string]interface{}{{
+				"host":      source.Host,
+				"port":      int(source.Port),
+				"user":      source.User,
+				"password":  source.Password,
+				"databases": source.Databases,
+			}})
+		}
+	}
+	return nil
+}
+
+func resourceAlicloudLogIngestionUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	parts, err := ParseResourceId(d.Id(), 2)
+	if err != nil {
+		return WrapError(err)
+	}
+	project := parts[0]
+
+	ingestion, err := buildIngestionFromResourceData(d)
+	if err != nil {
+		return WrapError(err)
+	}
+
+	conn, err := client.NewSlsClient()
+	if err != nil {
+		return WrapError(err)
+	}
+	if err := resource.Retry(5*time.Minute, func() *resource.RetryError {
+		if err := conn.UpdateIngestion(project, ingestion); err != nil {
+			if NeedRetry(err) {
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	}); err != nil {
+		return WrapErrorf(err, DefaultErrorMsg, d.Id(), "UpdateIngestion", AlibabaCloudSdkGoERROR)
+	}
+	addDebug("UpdateIngestion", ingestion, map[string]interface{}{"project": project})
+
+	return resourceAlicloudLogIngestionRead(d, meta)
+}
+
+func resourceAlicloudLogIngestionDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	parts, err := ParseResourceId(d.Id(), 2)
+	if err != nil {
+		return WrapError(err)
+	}
+	project, name := parts[0], parts[1]
+
+	conn, err := client.NewSlsClient()
+	if err != nil {
+		return WrapError(err)
+	}
+	if err := resource.Retry(5*time.Minute, func() *resource.RetryError {
+		if err := conn.DeleteIngestion(project, name); err != nil {
+			if NeedRetry(err) {
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	}); err != nil {
+		if IsExpectedErrors(err, []string{"IngestionNotExist", "JobNotExist"}) {
+			return nil
+		}
+		return WrapErrorf(err, DefaultErrorMsg, d.Id(), "DeleteIngestion", AlibabaCloudSdkGoERROR)
+	}
+	return nil
+}
+
+func resourceAlicloudLogIngestionImport(d *schema.ResourceData, meta interface{}) ([]*schema.ResourceData, error) {
+	parts, err := ParseResourceId(d.Id(), 2)
+	if err != nil {
+		return nil, WrapError(err)
+	}
+	d.Set("project_name", parts[0])
+	d.Set("name", parts[1])
+	return []*schema.ResourceData{d}, nil
+}
@@ -0,0 +1,358 @@
+package alicloud
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	sls "github.com/aliyun/aliyun-log-go-sdk"
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/validation"
+)
+
+func resourceAlicloudLogScheduledSql() *schema.Resource {
+	return &schema.Resource{
+		Create: resourceAlicloudLogScheduledSqlCreate,
+		Read:   resourceAlicloudLogScheduledSqlRead,
+		Update: resourceAlicloudLogScheduledSqlUpdate,
+		Delete: resourceAlicloudLogScheduledSqlDelete,
+		Importer: &schema.ResourceImporter{
+			State: schema.ImportStatePassthrough,
+		},
+		Timeouts: &schema.ResourceTimeout{
+			Create: schema.DefaultTimeout(5 * time.Minute),
+			Update: schema.DefaultTimeout(5 * time.Minute),
+			Delete: schema.DefaultTimeout(5 * time.Minute),
+		},
+		Schema: map[string]*schema.Schema{
+			"project_name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"name": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"display_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"description": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"src_logstore": {
+				Type:     schema.TypeString,
+				Required: true,
+				ForceNew: true,
+			},
+			"dest_endpoint": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"dest_project_name": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"dest_logstore": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"dest_role_arn": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"script": {
+				Type:     schema.TypeString,
+				Required: true,
+			},
+			"sql_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "scheduledSQL",
+				ValidateFunc: validation.StringInSlice([]string{"scheduledSQL", "searchQuery"}, false),
+			},
+			"from_time": {
+				Type:     schema.TypeInt,
+				Required: true,
+			},
+			"from_time_expr": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"to_time": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  0,
+			},
+			"to_time_expr": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"schedule_type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "FixedRate",
+				ValidateFunc: validation.StringInSlice([]string{"FixedRate", "Cron", "Hourly", "Daily", "Weekly"}, false),
+			},
+			"schedule_interval": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"cron_expression": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"time_zone": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"delay": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  0,
+			},
+			"max_run_time_in_seconds": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  3600,
+			},
+			"max_retries": {
+				Type:     schema.TypeInt,
+				Optional: true,
+				Default:  3,
+			},
+			"resource_pool": {
+				Type:     schema.TypeString,
+				Optional: true,
+			},
+			"enable": {
+				Type:     schema.TypeBool,
+				Optional: true,
+				Default:  true,
+			},
+		},
+	}
+}
+
+func resourceAlicloudLogScheduledSqlCreate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	project := d.Get("project_name").(string)
+	name := d.Get("name").(string)
+
+	job := sls.ScheduledSQL{
+		Name:        name,
+		DisplayName: d.Get("display_name").(string),
+		Description: d.Get("description").(string),
+		Configuration: &sls.ScheduledSQLConfiguration{
+			SourceLogstore:      d.Get("src_logstore").(string),
+			DestEndpoint:        d.Get("dest_endpoint").(string),
+			DestProjectName:     d.Get("dest_project_name").(string),
+			DestLogstore:        d.Get("dest_logstore").(string),
+			DestRoleArn:         d.Get("dest_role_arn").(string),
+			Script:              d.Get("script").(string),
+			SqlType:             d.Get("sql_type").(string),
+			FromTime:            int64(d.Get("from_time").(int)),
+			FromTimeExpr:        d.Get("from_time_expr").(string),
+			ToTime:              int64(d.Get("to_time").(int)),
+			ToTimeExpr:          d.Get("to_time_expr").(string),
+			MaxRunTimeInSeconds: int32(d.Get("max_run_time_in_seconds").(int)),
+			MaxRetries:          int32(d.Get("max_retries").(int)),
+			ResourcePool:        d.Get("resource_pool").(string),
+			Schedule: &sls.Schedule{
+				Type:           d.Get("schedule_type").(string),
+				Interval:       d.Get("schedule_interval").(string),
+				CronExpression: d.Get("cron_expression").(string),
+				TimeZone:       d.Get("time_zone").(string),
+				Delay:          int32(d.Get("delay").(int)),
+			},
+		},
+	}
+
+	conn, err := client.NewSlsClient()
+	if err != nil {
+		return WrapError(err)
+	}
+	if err := resource.Retry(5*time.Minute, func() *resource.RetryError {
+		if err := conn.CreateScheduledSQL(project, &job); err != nil {
+			if NeedRetry(err) {
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	}); err != nil {
+		return WrapErrorf(err, DefaultErrorMsg, "alicloud_log_scheduled_sql", "CreateScheduledSQL", AlibabaCloudSdkGoERROR)
+	}
+	addDebug("CreateScheduledSQL", job, map[string]interface{}{"project": project, "name": name})
+
+	d.SetId(fmt.Sprintf("%s%s%s", project, COLON_SEPARATED, name))
+
+	if !d.Get("enable").(bool) {
+		logService := LogService{client}
+		if err := logService.SetLogScheduledSqlState(project, name, false); err != nil {
+			return WrapError(err)
+		}
+	}
+
+	return resourceAlicloudLogScheduledSqlRead(d, meta)
+}
+
+func resourceAlicloudLogScheduledSqlRead(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	logService := LogService{client}
+
+	object, err := logService.DescribeLogScheduledSql(d.Id())
+	if err != nil {
+		if NotFoundError(err) {
+			log.Printf("[DEBUG] Resource alicloud_log_scheduled_sql logService.DescribeLogScheduledSql Failed!!! %s", err)
+			d.SetId("")
+			return nil
+		}
+		return WrapError(err)
+	}
+
+	parts, err := ParseResourceId(d.Id(), 2)
+	if err != nil {
+		return WrapError(err)
+	}
+	d.Set("project_name", parts[0])
+	d.Set("name", object.Name)
+	d.Set("display_name", object.DisplayName)
+	d.Set("description", object.Description)
+	if c := object.Configuration; c != nil {
+		d.Set("src_logstore", c.SourceLogstore)
+		d.Set("dest_endpoint", c.DestEndpoint)
+		d.Set("dest_project_name", c.DestProjectName)
+		d.Set("dest_logstore", c.DestLogstore)
+		d.Set("dest_role_arn", c.DestRoleArn)
+		d.Set("script", c.Script)
+		d.Set("sql_type", c.SqlType)
+		d.Set("from_time", int(c.FromTime))
+		d.Set("from_time_expr", c.FromTimeExpr)
+		d.Set("to_time", int(c.ToTime))
+		d.Set("to_time_expr", c.ToTimeExpr)
+		d.Set("max_run_time_in_seconds", int(c.MaxRunTimeInSeconds))
+		d.Set("max_retries", int(c.MaxRetries))
+		d.Set("resource_pool", c.ResourcePool)
+		if c.Schedule != nil {
+			d.Set("schedule_type", c.Schedule.Type)
+			d.Set("schedule_interval", c.Schedule.Interval)
+			d.Set("cron_expression", c.Schedule.CronExpression)
+			d.Set("time_zone", c.Schedule.TimeZone)
+			d.Set("delay", int(c.Schedule.Delay))
+		}
+	}
+	d.Set("enable", fmt.Sprint(object.Status) != "DISABLED")
+
+	return nil
+}
+
+func resourceAlicloudLogScheduledSqlUpdate(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	logService := LogService{client}
+	parts, err := ParseResourceId(d.Id(), 2)
+	if err != nil {
+		return WrapError(err)
+	}
+	project, name := parts[0], parts[1]
+
+	update := false
+	for _, key := range []string{"display_name", "description", "script", "sql_type", "dest_endpoint",
+		"dest_project_name", "dest_logstore", "dest_role_arn", "from_time", "from_time_expr", "to_time", "to_time_expr",
+		"max_run_time_in_seconds", "max_retries", "resource_pool", "schedule_type",
+		"schedule_interval", "cron_expression", "time_zone", "delay"} {
+		if d.HasChange(key) {
+			update = true
+			break
+		}
+	}
+
+	if update {
+		job := sls.ScheduledSQL{
+			Name:        name,
+			DisplayName: d.Get("display_name").(string),
+			Description: d.Get("description").(string),
+			Configuration: &sls.ScheduledSQLConfiguration{
+				SourceLogstore:      d.Get("src_logstore").(string),
+				DestEndpoint:        d.Get("dest_endpoint").(string),
+				DestProjectName:     d.Get("dest_project_name").(string),
+				DestLogstore:        d.Get("dest_logstore").(string),
+				DestRoleArn:         d.Get("dest_role_arn").(string),
+				Script:              d.Get("script").(string),
+				SqlType:             d.Get("sql_type").(string),
+				FromTime:            int64(d.Get("from_time").(int)),
+				FromTimeExpr:        d.Get("from_time_expr").(string),
+				ToTime:              int64(d.Get("to_time").(int)),
+				ToTimeExpr:          d.Get("to_time_expr").(string),
+				MaxRunTimeInSeconds: int32(d.Get("max_run_time_in_seconds").(int)),
+				MaxRetries:          int32(d.Get("max_retries").(int)),
+				ResourcePool:        d.Get("resource_pool").(string),
+				Schedule: &sls.Schedule{
+					Type:           d.Get("schedule_type").(string),
+					Interval:       d.Get("schedule_interval").(string),
+					CronExpression: d.Get("cron_expression").(string),
+					TimeZone:       d.Get("time_zone").(string),
+					Delay:          int32(d.Get("delay").(int)),
+				},
+			},
+		}
+		conn, err := client.NewSlsClient()
+		if err != nil {
+			return WrapError(err)
+		}
+		if err := resource.Retry(5*time.Minute, func() *resource.RetryError {
+			if err := conn.UpdateScheduledSQL(project, &job); err != nil {
+				if NeedRetry(err) {
+					return resource.RetryableError(err)
+				}
+				return resource.NonRetryableError(err)
+			}
+			return nil
+		}); err != nil {
+			return WrapErrorf(err, DefaultErrorMsg, d.Id(), "UpdateScheduledSQL", AlibabaCloudSdkGoERROR)
+		}
+		addDebug("UpdateScheduledSQL", job, map[string]interface{}{"project": project, "name": name})
+	}
+
+	if d.HasChange("enable") {
+		if err := logService.SetLogScheduledSqlState(project, name, d.Get("enable").(bool)); err != nil {
+			return WrapError(err)
+		}
+	}
+
+	return resourceAlicloudLogScheduledSqlRead(d, meta)
+}
+
+func resourceAlicloudLogScheduledSqlDelete(d *schema.ResourceData, meta interface{}) error {
+	client := meta.(*connectivity.AliyunClient)
+	parts, err := ParseResourceId(d.Id(), 2)
+	if err != nil {
+		return WrapError(err)
+	}
+	project, name := parts[0], parts[1]
+
+	conn, err := client.NewSlsClient()
+	if err != nil {
+		return WrapError(err)
+	}
+	if err := resource.Retry(5*time.Minute, func() *resource.RetryError {
+		if err := conn.DeleteScheduledSQL(project, name); err != nil {
+			if NeedRetry(err) {
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	}); err != nil {
+		if IsExpectedErrors(err, []string{"ScheduledSQLNotExist", "JobNotExist"}) {
+			return nil
+		}
+		return WrapErrorf(err, DefaultErrorMsg, d.Id(), "DeleteScheduledSQL", AlibabaCloudSdkGoERROR)
+	}
+	return nil
+}
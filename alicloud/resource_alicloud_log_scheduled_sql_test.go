@@ -0,0 +1,159 @@
+package alicloud
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/terraform"
+)
+
+// testAccCheckLogScheduledSqlInstanceFired polls ListScheduledSQLJobInstances
+// until at least one job instance shows up, verifying the schedule actually
+// fires rather than just that the resource's own fields round-trip.
+func testAccCheckLogScheduledSqlInstanceFired(resourceId string) resource.TestCheckFunc {
+	return func(s *terraform.State) error {
+		rs, ok := s.RootModule().Resources[resourceId]
+		if !ok {
+			return WrapError(fmt.Errorf("resource %s not found in state", resourceId))
+		}
+		parts, err := ParseResourceId(rs.Primary.ID, 2)
+		if err != nil {
+			return WrapError(err)
+		}
+		project, name := parts[0], parts[1]
+
+		client := testAccProvider.Meta().(*connectivity.AliyunClient)
+		conn, err := client.NewSlsClient()
+		if err != nil {
+			return WrapError(err)
+		}
+
+		return resource.Retry(5*time.Minute, func() *resource.RetryError {
+			instances, _, _, err := conn.ListScheduledSQLJobInstances(project, name, nil)
+			if err != nil {
+				return resource.NonRetryableError(WrapError(err))
+			}
+			if len(instances) == 0 {
+				return resource.RetryableError(fmt.Errorf("waiting for the ScheduledSQL job %s.%s to produce at least one instance", project, name))
+			}
+			return nil
+		})
+	}
+}
+
+func TestAccAlicloudLogScheduledSql_basic(t *testing.T) {
+	var v map[string]interface{}
+	resourceId := "alicloud_log_scheduled_sql.default"
+	ra := resourceAttrInit(resourceId, nil)
+	serviceFunc := func() interface{} {
+		return &LogService{client: testAccProvider.Meta().(*connectivity.AliyunClient)}
+	}
+	rc := resourceCheckInitWithDescribeMethod(resourceId, &v, serviceFunc, "DescribeLogScheduledSql")
+	rand := acctest.RandIntRange(1000, 9999)
+	name := fmt.Sprintf("tf-testacc-logscheduledsql-%d", rand)
+
+	resource.Test(t, resource.TestCase{
+		PreCheck:     func() { testAccPreCheck(t) },
+		Providers:    testAccProviders,
+		CheckDestroy: rc.checkResourceDestroy(),
+		Steps: []resource.TestStep{
+			{
+				Config: testAccLogScheduledSqlConfig(name),
+				Check: resource.ComposeTestCheckFunc(
+					rc.checkResourceExists(),
+					resource.TestCheckResourceAttr(resourceId, "name", name),
+					resource.TestCheckResourceAttr(resourceId, "enable", "true"),
+					testAccCheckLogScheduledSqlInstanceFired(resourceId),
+				),
+			},
+			{
+				Config: testAccLogScheduledSqlConfigUpdate(name),
+				Check: resource.ComposeTestCheckFunc(
+					rc.checkResourceExists(),
+					resource.TestCheckResourceAttr(resourceId, "enable", "false"),
+				),
+			},
+			{
+				ResourceName:      resourceId,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
+		},
+	})
+}
+
+func testAccLogScheduledSqlConfig(name string) string {
+	return fmt.Sprintf(`
+variable "name" {
+  default = "%s"
+}
+
+resource "alicloud_log_project" "default" {
+  name        = var.name
+  description = "tf-testacc-log-scheduledsql"
+}
+
+resource "alicloud_log_store" "src" {
+  project = alicloud_log_project.default.name
+  name    = "${var.name}-src"
+}
+
+resource "alicloud_log_store" "dest" {
+  project = alicloud_log_project.default.name
+  name    = "${var.name}-dest"
+}
+
+resource "alicloud_log_scheduled_sql" "default" {
+  project_name      = alicloud_log_project.default.name
+  name              = var.name
+  src_logstore      = alicloud_log_store.src.name
+  dest_project_name = alicloud_log_project.default.name
+  dest_logstore     = alicloud_log_store.dest.name
+  script            = "select count(*) as cnt, __time__ from log limit 1000"
+  from_time         = 1600000000
+  schedule_type     = "FixedRate"
+  schedule_interval = "1h"
+  enable            = true
+}
+`, name)
+}
+
+func testAccLogScheduledSqlConfigUpdate(name string) string {
+	return fmt.Sprintf(`
+variable "name" {
+  default = "%s"
+}
+
+resource "alicloud_log_project" "default" {
+  name        = var.name
+  description = "tf-testacc-log-scheduledsql"
+}
+
+resource "alicloud_log_store" "src" {
+  project = alicloud_log_project.default.name
+  name    = "${var.name}-src"
+}
+
+resource "alicloud_log_store" "dest" {
+  project = alicloud_log_project.default.name
+  name    = "${var.name}-dest"
+}
+
+resource "alicloud_log_scheduled_sql" "default" {
+  project_name      = alicloud_log_project.default.name
+  name              = var.name
+  src_logstore      = alicloud_log_store.src.name
+  dest_project_name = alicloud_log_project.default.name
+  dest_logstore     = alicloud_log_store.dest.name
+  script            = "select count(*) as cnt, __time__ from log limit 1000"
+  from_time         = 1600000000
+  schedule_type     = "FixedRate"
+  schedule_interval = "1h"
+  enable            = false
+}
+`, name)
+}
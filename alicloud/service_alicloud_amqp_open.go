@@ -1,12 +1,7 @@
 package alicloud
 
 import (
-	"time"
-
-	"github.com/PaesslerAG/jsonpath"
-	util "github.com/alibabacloud-go/tea-utils/service"
 	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
-	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
 )
 
 type AmqpOpenService struct {
@@ -14,64 +9,37 @@ type AmqpOpenService struct {
 }
 
 func (s *AmqpOpenService) DescribeAmqpVirtualHost(id string) (object map[string]interface{}, err error) {
-	var response map[string]interface{}
 	conn, err := s.client.NewOnsproxyClient()
 	if err != nil {
 		return nil, WrapError(err)
 	}
-	action := "ListVirtualHosts"
 	parts, err := ParseResourceId(id, 2)
 	if err != nil {
-		err = WrapError(err)
-		return
-	}
-	request := map[string]interface{}{
-		"RegionId":   s.client.RegionId,
-		"InstanceId": parts[0],
-		"MaxResults": 100,
+		return nil, WrapError(err)
 	}
-	idExist := false
-	for {
-		runtime := util.RuntimeOptions{}
-		runtime.SetAutoretry(true)
-		wait := incrementalWait(3*time.Second, 3*time.Second)
-		err = resource.Retry(5*time.Minute, func() *resource.RetryError {
-			response, err = conn.DoRequest(StringPointer(action), nil, StringPointer("GET"), StringPointer("2019-12-12"), StringPointer("AK"), request, nil, &runtime)
-			if err != nil {
-				if NeedRetry(err) {
-					wait()
-					return resource.RetryableError(err)
-				}
-				return resource.NonRetryableError(err)
-			}
-			return nil
-		})
-		addDebug(action, response, request)
-		if err != nil {
-			return object, WrapErrorf(err, DefaultErrorMsg, id, action, AlibabaCloudSdkGoERROR)
-		}
-		v, err := jsonpath.Get("$.Data.VirtualHosts", response)
-		if err != nil {
-			return object, WrapErrorf(err, FailedGetAttributeMsg, id, "$.Data.VirtualHosts", response)
-		}
-		if len(v.([]interface{})) < 1 {
-			return object, WrapErrorf(Error(GetNotFoundMessage("Amqp", id)), NotFoundWithResponse, response)
-		}
-		for _, v := range v.([]interface{}) {
-			if v.(map[string]interface{})["Name"].(string) == parts[1] {
-				idExist = true
-				return v.(map[string]interface{}), nil
-			}
-		}
 
-		if nextToken, ok := response["NextToken"].(string); ok && nextToken != "" {
-			request["NextToken"] = nextToken
-		} else {
-			break
-		}
+	items, response, err := PaginatedList(PaginatedListRequest{
+		Conn:     conn,
+		Action:   "ListVirtualHosts",
+		Method:   "GET",
+		Version:  "2019-12-12",
+		AuthType: "AK",
+		Request: map[string]interface{}{
+			"RegionId":   s.client.RegionId,
+			"InstanceId": parts[0],
+			"MaxResults": 100,
+		},
+		DataPath: "$.Data.VirtualHosts",
+		Match: func(item map[string]interface{}) bool {
+			name, ok := item["Name"].(string)
+			return ok && name == parts[1]
+		},
+	})
+	if err != nil {
+		return nil, WrapErrorf(err, DefaultErrorMsg, id, "ListVirtualHosts", AlibabaCloudSdkGoERROR)
 	}
-	if !idExist {
-		return object, WrapErrorf(Error(GetNotFoundMessage("Amqp", id)), NotFoundWithResponse, response)
+	if len(items) == 0 {
+		return nil, WrapErrorf(Error(GetNotFoundMessage("Amqp", id)), NotFoundWithResponse, response)
 	}
-	return
+	return items[0].(map[string]interface{}), nil
 }
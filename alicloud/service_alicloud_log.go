@@ -0,0 +1,223 @@
+package alicloud
+
+import (
+	"fmt"
+	"time"
+
+	sls "github.com/aliyun/aliyun-log-go-sdk"
+	"github.com/aliyun/terraform-provider-alicloud/alicloud/connectivity"
+	"github.com/hashicorp/terraform-plugin-sdk/helper/resource"
+)
+
+// LogService wraps the SLS ClientInterface so that all `alicloud_log_*`
+// resources share the same Describe/Wait conventions used across the
+// provider.
+type LogService struct {
+	client *connectivity.AliyunClient
+}
+
+func (s *LogService) DescribeLogScheduledSql(id string) (object *sls.ScheduledSQL, err error) {
+	parts, err := ParseResourceId(id, 2)
+	if err != nil {
+		return nil, WrapError(err)
+	}
+	project, name := parts[0], parts[1]
+	conn, err := s.client.NewSlsClient()
+	if err != nil {
+		return nil, WrapError(err)
+	}
+	wait := incrementalWait(2*time.Second, 2*time.Second)
+	err = resource.Retry(5*time.Minute, func() *resource.RetryError {
+		object, err = conn.GetScheduledSQL(project, name)
+		if err != nil {
+			if NeedRetry(err) {
+				wait()
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	addDebug("GetScheduledSQL", object, map[string]interface{}{"project": project, "name": name})
+	if err != nil {
+		if IsExpectedErrors(err, []string{"ScheduledSQLNotExist", "JobNotExist"}) {
+			return object, WrapErrorf(Error(GetNotFoundMessage("LogScheduledSql", id)), NotFoundMsg, ProviderERROR)
+		}
+		return object, WrapErrorf(err, DefaultErrorMsg, id, "GetScheduledSQL", AlibabaCloudSdkGoERROR)
+	}
+	if object == nil {
+		return object, WrapErrorf(Error(GetNotFoundMessage("LogScheduledSql", id)), NotFoundMsg, ProviderERROR)
+	}
+	return object, nil
+}
+
+// SetLogScheduledSqlState reconciles the desired `enable` flag against the
+// most recent job instance via ModifyScheduledSQLJobInstanceState. SLS does
+// not expose a dedicated enable/disable API on the ScheduledSQL job itself,
+// only a per-instance state transition, so the latest instance is used as
+// the handle. A freshly created job (especially one with a future
+// `from_time`) may not have produced any instance yet, so this polls for
+// one to appear instead of silently no-op'ing: without an instance to
+// transition, `enable = false` would otherwise have no effect and the job
+// would still fire on its schedule.
+func (s *LogService) SetLogScheduledSqlState(project, name string, enable bool) error {
+	conn, err := s.client.NewSlsClient()
+	if err != nil {
+		return WrapError(err)
+	}
+
+	var latest *sls.ScheduledSQLJobInstance
+	wait := incrementalWait(5*time.Second, 5*time.Second)
+	err = resource.Retry(3*time.Minute, func() *resource.RetryError {
+		instances, _, _, err := conn.ListScheduledSQLJobInstances(project, name, nil)
+		if err != nil {
+			if NeedRetry(err) {
+				wait()
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		if len(instances) == 0 {
+			wait()
+			return resource.RetryableError(Error("no ScheduledSQL job instance has been created yet for %s.%s", project, name))
+		}
+		latest = instances[0]
+		return nil
+	})
+	if err != nil {
+		return WrapErrorf(err, DefaultErrorMsg, name, "ListScheduledSQLJobInstances", AlibabaCloudSdkGoERROR)
+	}
+
+	state := sls.ScheduledSQLState("DISABLED")
+	if enable {
+		state = sls.ScheduledSQLState("RUNNING")
+	}
+	if err := conn.ModifyScheduledSQLJobInstanceState(project, name, latest.InstanceId, state); err != nil {
+		return WrapErrorf(err, DefaultErrorMsg, name, "ModifyScheduledSQLJobInstanceState", AlibabaCloudSdkGoERROR)
+	}
+	return nil
+}
+
+func (s *LogService) DescribeLogIngestion(id string) (object *sls.Ingestion, err error) {
+	parts, err := ParseResourceId(id, 2)
+	if err != nil {
+		return nil, WrapError(err)
+	}
+	project, name := parts[0], parts[1]
+	conn, err := s.client.NewSlsClient()
+	if err != nil {
+		return nil, WrapError(err)
+	}
+	wait := incrementalWait(2*time.Second, 2*time.Second)
+	err = resource.Retry(5*time.Minute, func() *resource.RetryError {
+		object, err = conn.GetIngestion(project, name)
+		if err != nil {
+			if NeedRetry(err) {
+				wait()
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	addDebug("GetIngestion", object, map[string]interface{}{"project": project, "name": name})
+	if err != nil {
+		if IsExpectedErrors(err, []string{"IngestionNotExist", "JobNotExist"}) {
+			return object, WrapErrorf(Error(GetNotFoundMessage("LogIngestion", id)), NotFoundMsg, ProviderERROR)
+		}
+		return object, WrapErrorf(err, DefaultErrorMsg, id, "GetIngestion", AlibabaCloudSdkGoERROR)
+	}
+	if object == nil {
+		return object, WrapErrorf(Error(GetNotFoundMessage("LogIngestion", id)), NotFoundMsg, ProviderERROR)
+	}
+	return object, nil
+}
+
+func (s *LogService) DescribeLogExport(id string) (object *sls.Export, err error) {
+	parts, err := ParseResourceId(id, 2)
+	if err != nil {
+		return nil, WrapError(err)
+	}
+	project, name := parts[0], parts[1]
+	conn, err := s.client.NewSlsClient()
+	if err != nil {
+		return nil, WrapError(err)
+	}
+	wait := incrementalWait(2*time.Second, 2*time.Second)
+	err = resource.Retry(5*time.Minute, func() *resource.RetryError {
+		object, err = conn.GetExport(project, name)
+		if err != nil {
+			if NeedRetry(err) {
+				wait()
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	addDebug("GetExport", object, map[string]interface{}{"project": project, "name": name})
+	if err != nil {
+		if IsExpectedErrors(err, []string{"ExportNotExist", "JobNotExist"}) {
+			return object, WrapErrorf(Error(GetNotFoundMessage("LogExport", id)), NotFoundMsg, ProviderERROR)
+		}
+		return object, WrapErrorf(err, DefaultErrorMsg, id, "GetExport", AlibabaCloudSdkGoERROR)
+	}
+	if object == nil {
+		return object, WrapErrorf(Error(GetNotFoundMessage("LogExport", id)), NotFoundMsg, ProviderERROR)
+	}
+	return object, nil
+}
+
+func (s *LogService) DescribeLogEtl(id string) (object *sls.ETL, err error) {
+	parts, err := ParseResourceId(id, 2)
+	if err != nil {
+		return nil, WrapError(err)
+	}
+	project, name := parts[0], parts[1]
+	conn, err := s.client.NewSlsClient()
+	if err != nil {
+		return nil, WrapError(err)
+	}
+	wait := incrementalWait(2*time.Second, 2*time.Second)
+	err = resource.Retry(5*time.Minute, func() *resource.RetryError {
+		object, err = conn.GetETL(project, name)
+		if err != nil {
+			if NeedRetry(err) {
+				wait()
+				return resource.RetryableError(err)
+			}
+			return resource.NonRetryableError(err)
+		}
+		return nil
+	})
+	addDebug("GetETL", object, map[string]interface{}{"project": project, "name": name})
+	if err != nil {
+		if IsExpectedErrors(err, []string{"ETLNotExist", "JobNotExist"}) {
+			return object, WrapErrorf(Error(GetNotFoundMessage("LogEtl", id)), NotFoundMsg, ProviderERROR)
+		}
+		return object, WrapErrorf(err, DefaultErrorMsg, id, "GetETL", AlibabaCloudSdkGoERROR)
+	}
+	if object == nil {
+		return object, WrapErrorf(Error(GetNotFoundMessage("LogEtl", id)), NotFoundMsg, ProviderERROR)
+	}
+	return object, nil
+}
+
+func (s *LogService) LogScheduledSqlStateRefreshFunc(id string, failStates []string) resource.StateRefreshFunc {
+	return func() (interface{}, string, error) {
+		object, err := s.DescribeLogScheduledSql(id)
+		if err != nil {
+			if NotFoundError(err) {
+				return nil, "", nil
+			}
+			return nil, "", WrapError(err)
+		}
+		status := fmt.Sprint(object.Status)
+		for _, failState := range failStates {
+			if status == failState {
+				return object, status, WrapError(Error(FailedToReachTargetStatus, status))
+			}
+		}
+		return object, status, nil
+	}
+}
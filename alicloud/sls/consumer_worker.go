@@ -0,0 +1,299 @@
+package sls
+
+import (
+	"context"
+	"expvar"
+	"sync"
+	"time"
+
+	sdk "github.com/aliyun/aliyun-log-go-sdk"
+)
+
+var (
+	consumerBatchesProcessed = expvar.NewInt("sls_consumer_batches_processed")
+	consumerBytesProcessed   = expvar.NewInt("sls_consumer_bytes_processed")
+	consumerLagSeconds       = expvar.NewFloat("sls_consumer_lag_seconds")
+)
+
+// ProcessFunc handles the logs pulled for one shard and returns the
+// checkpoint to commit once it has durably processed them.
+type ProcessFunc func(shard int, logs *sdk.LogGroupList) (nextCheckpoint string, err error)
+
+// ConsumerWorkerConfig tunes heartbeat/commit cadence and PullLogs retry
+// behavior. Zero values fall back to the documented defaults in
+// NewConsumerWorker.
+type ConsumerWorkerConfig struct {
+	Project               string
+	Logstore              string
+	ConsumerGroupName     string
+	ConsumerName          string
+	HeartbeatIntervalSec  int
+	AutoCommitIntervalSec int
+	BaseRetryBackoff      time.Duration
+	MaxRetryBackoff       time.Duration
+	LogGroupMaxCount      int
+}
+
+// ConsumerWorker drives a consumer-group shard assignment: it heartbeats to
+// learn which shards it owns, starts one shard-processor goroutine per
+// owned shard that pulls logs from the last checkpoint and feeds them to
+// ProcessFunc, and commits checkpoints on the configured cadence.
+type ConsumerWorker struct {
+	client  sdk.ClientInterface
+	config  ConsumerWorkerConfig
+	process ProcessFunc
+
+	mu         sync.Mutex
+	processors map[int]*shardProcessor
+
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+// NewConsumerWorker creates a ConsumerWorker; call Run to start heartbeating
+// and processing shards.
+func NewConsumerWorker(client sdk.ClientInterface, config ConsumerWorkerConfig, process ProcessFunc) *ConsumerWorker {
+	if config.HeartbeatIntervalSec <= 0 {
+		config.HeartbeatIntervalSec = 20
+	}
+	if config.AutoCommitIntervalSec <= 0 {
+		config.AutoCommitIntervalSec = 60
+	}
+	if config.BaseRetryBackoff <= 0 {
+		config.BaseRetryBackoff = 500 * time.Millisecond
+	}
+	if config.MaxRetryBackoff <= 0 {
+		config.MaxRetryBackoff = 30 * time.Second
+	}
+	if config.LogGroupMaxCount <= 0 {
+		config.LogGroupMaxCount = 1000
+	}
+	return &ConsumerWorker{
+		client:     client,
+		config:     config,
+		process:    process,
+		processors: make(map[int]*shardProcessor),
+		stopCh:     make(chan struct{}),
+		doneCh:     make(chan struct{}),
+	}
+}
+
+// Run heartbeats on HeartbeatIntervalSec until Shutdown is called, starting
+// and stopping shard-processor goroutines as ownership changes.
+func (w *ConsumerWorker) Run() {
+	defer close(w.doneCh)
+	ticker := time.NewTicker(time.Duration(w.config.HeartbeatIntervalSec) * time.Second)
+	defer ticker.Stop()
+
+	w.heartbeat(nil)
+	for {
+		select {
+		case <-ticker.C:
+			w.heartbeat(w.ownedShards())
+		case <-w.stopCh:
+			w.mu.Lock()
+			owned := make([]int, 0, len(w.processors))
+			for shard := range w.processors {
+				owned = append(owned, shard)
+			}
+			w.mu.Unlock()
+			// Force-commit every shard's latest checkpoint before telling the
+			// server we hold none: a releasing heartbeat sent first would let
+			// another consumer pick up a shard and advance its checkpoint
+			// before our delayed commit lands, clobbering its progress with a
+			// stale one.
+			for _, shard := range owned {
+				w.stopShard(shard, true)
+			}
+			w.client.HeartBeat(w.config.Project, w.config.Logstore, w.config.ConsumerGroupName, w.config.ConsumerName, nil)
+			return
+		}
+	}
+}
+
+func (w *ConsumerWorker) ownedShards() []int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	shards := make([]int, 0, len(w.processors))
+	for shard := range w.processors {
+		shards = append(shards, shard)
+	}
+	return shards
+}
+
+func (w *ConsumerWorker) heartbeat(heldShardIDs []int) {
+	owned, err := w.client.HeartBeat(w.config.Project, w.config.Logstore, w.config.ConsumerGroupName, w.config.ConsumerName, heldShardIDs)
+	if err != nil {
+		return
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	stillOwned := make(map[int]bool, len(owned))
+	for _, shard := range owned {
+		stillOwned[shard] = true
+		if _, ok := w.processors[shard]; !ok {
+			w.processors[shard] = w.startShardLocked(shard)
+		}
+	}
+	for shard := range w.processors {
+		if !stillOwned[shard] {
+			w.stopShardLocked(shard, true)
+		}
+	}
+}
+
+// startShardLocked must be called with w.mu held.
+func (w *ConsumerWorker) startShardLocked(shard int) *shardProcessor {
+	sp := &shardProcessor{
+		worker: w,
+		shard:  shard,
+		stopCh: make(chan struct{}),
+		doneCh: make(chan struct{}),
+	}
+	go sp.run()
+	return sp
+}
+
+func (w *ConsumerWorker) stopShard(shard int, forceCommit bool) {
+	w.mu.Lock()
+	w.stopShardLocked(shard, forceCommit)
+	w.mu.Unlock()
+}
+
+// stopShardLocked must be called with w.mu held. It signals the shard
+// processor to drain its in-flight batch and force-commit before exiting,
+// then removes it from the owned set.
+func (w *ConsumerWorker) stopShardLocked(shard int, forceCommit bool) {
+	sp, ok := w.processors[shard]
+	if !ok {
+		return
+	}
+	delete(w.processors, shard)
+	close(sp.stopCh)
+	<-sp.doneCh
+}
+
+// Shutdown stops heartbeating and waits for every shard processor to finish
+// committing its checkpoint, or for ctx to be done.
+func (w *ConsumerWorker) Shutdown(ctx context.Context) error {
+	close(w.stopCh)
+	select {
+	case <-w.doneCh:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+type shardProcessor struct {
+	worker *ConsumerWorker
+	shard  int
+	stopCh chan struct{}
+	doneCh chan struct{}
+}
+
+func (sp *shardProcessor) run() {
+	defer close(sp.doneCh)
+	w := sp.worker
+
+	checkpoint := sp.initialCheckpoint()
+	commitTicker := time.NewTicker(time.Duration(w.config.AutoCommitIntervalSec) * time.Second)
+	defer commitTicker.Stop()
+
+	backoff := w.config.BaseRetryBackoff
+	for {
+		select {
+		case <-sp.stopCh:
+			w.client.UpdateCheckpoint(w.config.Project, w.config.Logstore, w.config.ConsumerGroupName, w.config.ConsumerName, sp.shard, checkpoint, true)
+			return
+		default:
+		}
+
+		logs, nextCursor, err := w.client.PullLogs(w.config.Project, w.config.Logstore, sp.shard, checkpoint, "", w.config.LogGroupMaxCount)
+		if err != nil {
+			time.Sleep(backoff)
+			backoff = nextBackoff(backoff, w.config.MaxRetryBackoff)
+			continue
+		}
+		backoff = w.config.BaseRetryBackoff
+
+		if logs != nil && len(logs.LogGroups) > 0 {
+			next, err := w.process(sp.shard, logs)
+			if err != nil {
+				time.Sleep(backoff)
+				backoff = nextBackoff(backoff, w.config.MaxRetryBackoff)
+				continue
+			}
+			checkpoint = next
+			consumerBatchesProcessed.Add(1)
+			consumerBytesProcessed.Add(logGroupListSize(logs))
+			if lastTime := lastLogTime(logs); lastTime > 0 {
+				consumerLagSeconds.Set(time.Since(time.Unix(lastTime, 0)).Seconds())
+			}
+		} else {
+			checkpoint = nextCursor
+		}
+
+		select {
+		case <-commitTicker.C:
+			w.client.UpdateCheckpoint(w.config.Project, w.config.Logstore, w.config.ConsumerGroupName, w.config.ConsumerName, sp.shard, checkpoint, false)
+		default:
+		}
+	}
+}
+
+func (sp *shardProcessor) initialCheckpoint() string {
+	w := sp.worker
+	checkpoints, err := w.client.GetCheckpoint(w.config.Project, w.config.Logstore, w.config.ConsumerGroupName)
+	if err == nil {
+		for _, cp := range checkpoints {
+			if cp.ShardID == sp.shard && cp.CheckPoint != "" {
+				return cp.CheckPoint
+			}
+		}
+	}
+	cursor, err := w.client.GetCursor(w.config.Project, w.config.Logstore, sp.shard, "begin")
+	if err != nil {
+		return ""
+	}
+	return cursor
+}
+
+func nextBackoff(current, max time.Duration) time.Duration {
+	next := current * 2
+	if next > max {
+		return max
+	}
+	return next
+}
+
+func logGroupListSize(logs *sdk.LogGroupList) int64 {
+	var size int64
+	for _, lg := range logs.LogGroups {
+		for _, log := range lg.Logs {
+			for _, c := range log.Contents {
+				if c.Key != nil {
+					size += int64(len(*c.Key))
+				}
+				if c.Value != nil {
+					size += int64(len(*c.Value))
+				}
+			}
+		}
+	}
+	return size
+}
+
+func lastLogTime(logs *sdk.LogGroupList) int64 {
+	var last int64
+	for _, lg := range logs.LogGroups {
+		for _, log := range lg.Logs {
+			if log.Time != nil && int64(*log.Time) > last {
+				last = int64(*log.Time)
+			}
+		}
+	}
+	return last
+}
@@ -0,0 +1,196 @@
+package sls
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	sdk "github.com/aliyun/aliyun-log-go-sdk"
+)
+
+// fakeConsumerClient implements just enough of sdk.ClientInterface to drive
+// a ConsumerWorker through one heartbeat/pull/commit cycle and a rebalance
+// that revokes the shard.
+type fakeConsumerClient struct {
+	sdk.ClientInterface
+
+	mu           sync.Mutex
+	owned        []int
+	pulls        int
+	commits      []string
+	forceCommits []bool
+}
+
+func (f *fakeConsumerClient) HeartBeat(project, logstore, cgName, consumer string, heldShardIDs []int) ([]int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.owned, nil
+}
+
+func (f *fakeConsumerClient) setOwned(shards []int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.owned = shards
+}
+
+func (f *fakeConsumerClient) GetCheckpoint(project, logstore, cgName string) ([]*sdk.ConsumerGroupCheckPoint, error) {
+	return nil, nil
+}
+
+// GetCursor only accepts "begin", "end", or a unix timestamp, per
+// client_interface.go's doc comment. The zero-value empty string and any
+// opaque checkpoint/cursor value are rejected, so a caller that mistakenly
+// feeds one back into GetCursor (instead of straight into PullLogs) fails
+// the test instead of silently returning a constant cursor.
+func (f *fakeConsumerClient) GetCursor(project, logstore string, shardID int, from string) (string, error) {
+	if from != "begin" && from != "end" {
+		if _, err := strconv.ParseInt(from, 10, 64); err != nil {
+			return "", fmt.Errorf("fakeConsumerClient: GetCursor called with invalid from %q", from)
+		}
+	}
+	return "cursor-0", nil
+}
+
+func (f *fakeConsumerClient) PullLogs(project, logstore string, shardID int, cursor, endCursor string, logGroupMaxCount int) (*sdk.LogGroupList, string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.pulls++
+	if f.pulls > 1 {
+		time.Sleep(5 * time.Millisecond)
+		return &sdk.LogGroupList{}, cursor, nil
+	}
+	return &sdk.LogGroupList{LogGroups: []*sdk.LogGroup{{}}}, "cursor-1", nil
+}
+
+func (f *fakeConsumerClient) UpdateCheckpoint(project, logstore, cgName, consumer string, shardID int, checkpoint string, forceSuccess bool) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.commits = append(f.commits, checkpoint)
+	f.forceCommits = append(f.forceCommits, forceSuccess)
+	return nil
+}
+
+func TestConsumerWorker_ProcessesOwnedShardAndShutsDownCleanly(t *testing.T) {
+	client := &fakeConsumerClient{owned: []int{0}}
+
+	var processed int32
+	var mu sync.Mutex
+	worker := NewConsumerWorker(client, ConsumerWorkerConfig{
+		Project:               "proj",
+		Logstore:              "store",
+		ConsumerGroupName:     "cg",
+		ConsumerName:          "consumer-1",
+		HeartbeatIntervalSec:  1,
+		AutoCommitIntervalSec: 1,
+	}, func(shard int, logs *sdk.LogGroupList) (string, error) {
+		mu.Lock()
+		processed++
+		mu.Unlock()
+		return "next-checkpoint", nil
+	})
+
+	go worker.Run()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		mu.Lock()
+		done := processed > 0
+		mu.Unlock()
+		if done || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := worker.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown: %v", err)
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if processed == 0 {
+		t.Fatal("expected ProcessFunc to be called at least once before shutdown")
+	}
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if len(client.commits) == 0 {
+		t.Fatal("expected at least one checkpoint commit")
+	}
+	if !client.forceCommits[len(client.forceCommits)-1] {
+		t.Fatal("expected the final checkpoint commit on shutdown to use forceSuccess=true")
+	}
+}
+
+// TestConsumerWorker_RevokedShardForceCommitsAndStops drives a rebalance: the
+// next heartbeat reports the shard is no longer owned, and the worker must
+// drain the in-flight batch, force-commit the current checkpoint, and tear
+// down the shard processor without a Shutdown call.
+func TestConsumerWorker_RevokedShardForceCommitsAndStops(t *testing.T) {
+	client := &fakeConsumerClient{owned: []int{0}}
+
+	var processed int32
+	var mu sync.Mutex
+	worker := NewConsumerWorker(client, ConsumerWorkerConfig{
+		Project:               "proj",
+		Logstore:              "store",
+		ConsumerGroupName:     "cg",
+		ConsumerName:          "consumer-1",
+		HeartbeatIntervalSec:  1,
+		AutoCommitIntervalSec: 1,
+	}, func(shard int, logs *sdk.LogGroupList) (string, error) {
+		mu.Lock()
+		processed++
+		mu.Unlock()
+		return "next-checkpoint", nil
+	})
+
+	go worker.Run()
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		worker.Shutdown(ctx)
+	}()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		mu.Lock()
+		done := processed > 0
+		mu.Unlock()
+		if done || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	client.setOwned(nil)
+
+	deadline = time.Now().Add(3 * time.Second)
+	for {
+		worker.mu.Lock()
+		stillOwned := len(worker.processors) == 0
+		worker.mu.Unlock()
+		if stillOwned || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	worker.mu.Lock()
+	_, stillHeld := worker.processors[0]
+	worker.mu.Unlock()
+	if stillHeld {
+		t.Fatal("expected shard processor for shard 0 to be torn down after its shard was revoked")
+	}
+
+	client.mu.Lock()
+	defer client.mu.Unlock()
+	if len(client.forceCommits) == 0 || !client.forceCommits[len(client.forceCommits)-1] {
+		t.Fatal("expected the revoked shard's last checkpoint commit to use forceSuccess=true")
+	}
+}
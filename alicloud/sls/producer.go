@@ -0,0 +1,308 @@
+// Package sls provides thin, provider-internal wrappers around the
+// aliyun-log-go-sdk ClientInterface for bulk log ingestion and consumption,
+// modeled on the upstream SLS Producer/Consumer libraries that the raw
+// single-call PutLogs/PullLogs APIs don't give Terraform resources for
+// free.
+package sls
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	sdk "github.com/aliyun/aliyun-log-go-sdk"
+)
+
+const (
+	DefaultMaxBatchSize     = 3 * 1024 * 1024
+	DefaultMaxBatchCount    = 4096
+	DefaultLingerMs         = 2000
+	DefaultTotalSizeLnBytes = 100 * 1024 * 1024
+	DefaultIoWorkerCount    = 8
+)
+
+// ProducerConfig tunes batching, compression and back-pressure for a
+// Producer. Zero values are replaced with the documented defaults in
+// NewProducer.
+type ProducerConfig struct {
+	MaxBatchSize     int
+	MaxBatchCount    int
+	LingerMs         int
+	TotalSizeLnBytes int64
+	IoWorkerCount    int
+	CompressType     int
+	// BlockOnFull, when true, makes SendLog block until the byte budget
+	// frees up instead of returning ErrProducerBufferFull.
+	BlockOnFull      bool
+	MaxRetries       int
+	BaseRetryBackoff time.Duration
+	MaxRetryBackoff  time.Duration
+}
+
+var ErrProducerBufferFull = errors.New("sls: producer buffer full")
+var ErrProducerClosed = errors.New("sls: producer is closed")
+
+type batchKey struct {
+	project   string
+	logstore  string
+	shardHash string
+	topic     string
+	source    string
+}
+
+type batch struct {
+	key    batchKey
+	topic  string
+	source string
+	group  *sdk.LogGroup
+	size   int
+	count  int
+}
+
+// Producer batches individual *sdk.Log entries per (project, logstore,
+// shardHash, topic, source) and flushes them via PutLogsWithCompressType from a pool of
+// I/O worker goroutines, bounded by a total in-flight byte budget.
+type Producer struct {
+	client sdk.ClientInterface
+	config ProducerConfig
+
+	mu      sync.Mutex
+	batches map[batchKey]*batch
+	closed  bool
+
+	budget *sizeSemaphore
+
+	flushCh chan *batch
+	wg      sync.WaitGroup
+
+	// sendWG tracks flushes that have been decided (batch removed from
+	// p.batches) but not yet sent on flushCh. Both SendLog's immediate
+	// flush and the linger timer's callback Add to it while still holding
+	// mu, so Close can never observe closed==true and an empty pending map
+	// while a send is still in flight; it waits on sendWG before closing
+	// flushCh, which would otherwise panic on a send to a closed channel.
+	sendWG sync.WaitGroup
+
+	timerMu sync.Mutex
+	timers  map[batchKey]*time.Timer
+}
+
+// NewProducer creates a Producer against client, applying defaults for any
+// zero-valued ProducerConfig fields, and starts its I/O worker pool.
+func NewProducer(client sdk.ClientInterface, config ProducerConfig) *Producer {
+	if config.MaxBatchSize <= 0 {
+		config.MaxBatchSize = DefaultMaxBatchSize
+	}
+	if config.MaxBatchCount <= 0 {
+		config.MaxBatchCount = DefaultMaxBatchCount
+	}
+	if config.LingerMs <= 0 {
+		config.LingerMs = DefaultLingerMs
+	}
+	if config.TotalSizeLnBytes <= 0 {
+		config.TotalSizeLnBytes = DefaultTotalSizeLnBytes
+	}
+	if config.IoWorkerCount <= 0 {
+		config.IoWorkerCount = DefaultIoWorkerCount
+	}
+	if config.CompressType == 0 {
+		config.CompressType = sdk.Compress_LZ4
+	}
+	if config.MaxRetries <= 0 {
+		config.MaxRetries = 5
+	}
+	if config.BaseRetryBackoff <= 0 {
+		config.BaseRetryBackoff = 200 * time.Millisecond
+	}
+	if config.MaxRetryBackoff <= 0 {
+		config.MaxRetryBackoff = 30 * time.Second
+	}
+
+	p := &Producer{
+		client:  client,
+		config:  config,
+		batches: make(map[batchKey]*batch),
+		budget:  newSizeSemaphore(config.TotalSizeLnBytes),
+		flushCh: make(chan *batch, config.IoWorkerCount*2),
+		timers:  make(map[batchKey]*time.Timer),
+	}
+	for i := 0; i < config.IoWorkerCount; i++ {
+		p.wg.Add(1)
+		go p.ioWorker()
+	}
+	return p
+}
+
+// SendLog enqueues a single log entry for project/logstore/topic/source,
+// grouped into a batch by shardHash as well. shardHash is not sent to SLS,
+// which picks the shard for every batch. It
+// flushes the batch immediately if MaxBatchSize or MaxBatchCount is
+// reached, and otherwise lets it flush after LingerMs.
+func (p *Producer) SendLog(project, logstore, shardHash, topic, source string, log *sdk.Log) error {
+	size := estimateLogSize(log)
+
+	if err := p.budget.acquire(int64(size), p.config.BlockOnFull); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		p.budget.release(int64(size))
+		return ErrProducerClosed
+	}
+	key := batchKey{project: project, logstore: logstore, shardHash: shardHash, topic: topic, source: source}
+	b, ok := p.batches[key]
+	if !ok {
+		b = &batch{key: key, topic: topic, source: source, group: &sdk.LogGroup{Topic: &topic, Source: &source}}
+		p.batches[key] = b
+		p.scheduleFlush(key)
+	}
+	b.group.Logs = append(b.group.Logs, log)
+	b.size += size
+	b.count++
+
+	var toFlush *batch
+	if b.size >= p.config.MaxBatchSize || b.count >= p.config.MaxBatchCount {
+		toFlush = b
+		delete(p.batches, key)
+		p.cancelFlush(key)
+		p.sendWG.Add(1)
+	}
+	p.mu.Unlock()
+
+	if toFlush != nil {
+		p.enqueueFlush(toFlush)
+		p.sendWG.Done()
+	}
+	return nil
+}
+
+func (p *Producer) scheduleFlush(key batchKey) {
+	p.timerMu.Lock()
+	defer p.timerMu.Unlock()
+	p.timers[key] = time.AfterFunc(time.Duration(p.config.LingerMs)*time.Millisecond, func() {
+		p.mu.Lock()
+		b, ok := p.batches[key]
+		if ok {
+			delete(p.batches, key)
+			p.sendWG.Add(1)
+		}
+		p.mu.Unlock()
+		if ok {
+			p.enqueueFlush(b)
+			p.sendWG.Done()
+		}
+	})
+}
+
+func (p *Producer) cancelFlush(key batchKey) {
+	p.timerMu.Lock()
+	defer p.timerMu.Unlock()
+	if t, ok := p.timers[key]; ok {
+		t.Stop()
+		delete(p.timers, key)
+	}
+}
+
+func (p *Producer) enqueueFlush(b *batch) {
+	p.flushCh <- b
+}
+
+func (p *Producer) ioWorker() {
+	defer p.wg.Done()
+	for b := range p.flushCh {
+		p.sendBatch(b)
+	}
+}
+
+// sendBatch retries with exponential backoff, distinguishing retryable
+// errors (NeedRetry) from terminal ones, up to MaxRetries attempts.
+func (p *Producer) sendBatch(b *batch) {
+	defer p.budget.release(int64(b.size))
+
+	backoff := p.config.BaseRetryBackoff
+	var err error
+	for attempt := 0; attempt <= p.config.MaxRetries; attempt++ {
+		err = p.client.PutLogsWithCompressType(b.key.project, b.key.logstore, b.group, p.config.CompressType)
+		if err == nil {
+			return
+		}
+		if !NeedRetry(err) || attempt == p.config.MaxRetries {
+			return
+		}
+		time.Sleep(backoff)
+		backoff *= 2
+		if backoff > p.config.MaxRetryBackoff {
+			backoff = p.config.MaxRetryBackoff
+		}
+	}
+}
+
+// Close stops accepting new logs, flushes every pending batch and waits for
+// in-flight sends to finish, up to timeout.
+func (p *Producer) Close(timeout time.Duration) error {
+	p.mu.Lock()
+	if p.closed {
+		p.mu.Unlock()
+		return nil
+	}
+	p.closed = true
+	pending := p.batches
+	p.batches = make(map[batchKey]*batch)
+	p.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		for key, b := range pending {
+			p.cancelFlush(key)
+			p.enqueueFlush(b)
+		}
+		// Wait for any flush whose batch was already removed from p.batches
+		// (and thus absent from pending above) but not yet sent on flushCh —
+		// see sendWG's doc comment.
+		p.sendWG.Wait()
+		close(p.flushCh)
+		p.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-time.After(timeout):
+		return errors.New("sls: producer close timed out waiting for in-flight batches")
+	}
+}
+
+func estimateLogSize(log *sdk.Log) int {
+	size := 16
+	for _, c := range log.Contents {
+		if c.Key != nil {
+			size += len(*c.Key)
+		}
+		if c.Value != nil {
+			size += len(*c.Value)
+		}
+	}
+	return size
+}
+
+// NeedRetry classifies SLS errors as retryable (throttling, server-side
+// transients, connection resets) versus terminal (bad request, auth
+// failures). It mirrors the provider's own NeedRetry used across describe
+// functions, duplicated here so this package has no dependency on the
+// alicloud package.
+func NeedRetry(err error) bool {
+	if err == nil {
+		return false
+	}
+	if slsErr, ok := err.(*sdk.Error); ok {
+		switch slsErr.Code {
+		case "RequestTimeout", "InternalServerError", "Unavailable", "Throttling", "WriteQuotaExceed":
+			return true
+		}
+		return false
+	}
+	return true
+}
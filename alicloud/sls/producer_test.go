@@ -0,0 +1,181 @@
+package sls
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	sdk "github.com/aliyun/aliyun-log-go-sdk"
+)
+
+// fakeClient implements the subset of sdk.ClientInterface the Producer
+// uses, recording every PutLogsWithCompressType call and optionally
+// failing the first failTimes calls (across all logstores) to exercise
+// retry/backoff.
+type fakeClient struct {
+	sdk.ClientInterface
+
+	mu        sync.Mutex
+	calls     []*sdk.LogGroup
+	failTimes int
+}
+
+func (f *fakeClient) PutLogsWithCompressType(project, logstore string, lg *sdk.LogGroup, compressType int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.failTimes > 0 {
+		f.failTimes--
+		return &sdk.Error{Code: "Throttling", Message: "slow down"}
+	}
+	f.calls = append(f.calls, lg)
+	return nil
+}
+
+func (f *fakeClient) callCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.calls)
+}
+
+func newTestLog(k, v string) *sdk.Log {
+	return &sdk.Log{Contents: []*sdk.LogContent{{Key: &k, Value: &v}}}
+}
+
+func TestProducer_FlushesOnMaxBatchCount(t *testing.T) {
+	client := &fakeClient{}
+	p := NewProducer(client, ProducerConfig{MaxBatchCount: 3, LingerMs: 60000, IoWorkerCount: 1})
+	defer p.Close(time.Second)
+
+	for i := 0; i < 3; i++ {
+		if err := p.SendLog("proj", "store", "", "topic", "source", newTestLog("k", "v")); err != nil {
+			t.Fatalf("SendLog: %v", err)
+		}
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for client.callCount() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if client.callCount() != 1 {
+		t.Fatalf("expected 1 flush once MaxBatchCount is reached, got %d", client.callCount())
+	}
+}
+
+func TestProducer_FlushesOnLinger(t *testing.T) {
+	client := &fakeClient{}
+	p := NewProducer(client, ProducerConfig{MaxBatchCount: 100, LingerMs: 20, IoWorkerCount: 1})
+	defer p.Close(time.Second)
+
+	if err := p.SendLog("proj", "store", "", "topic", "source", newTestLog("k", "v")); err != nil {
+		t.Fatalf("SendLog: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for client.callCount() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if client.callCount() != 1 {
+		t.Fatalf("expected linger-triggered flush, got %d calls", client.callCount())
+	}
+}
+
+func TestProducer_RetriesRetryableErrors(t *testing.T) {
+	client := &fakeClient{failTimes: 2}
+	p := NewProducer(client, ProducerConfig{
+		MaxBatchCount:    1,
+		LingerMs:         60000,
+		IoWorkerCount:    1,
+		BaseRetryBackoff: time.Millisecond,
+		MaxRetryBackoff:  5 * time.Millisecond,
+	})
+	defer p.Close(time.Second)
+
+	if err := p.SendLog("proj", "store", "", "topic", "source", newTestLog("k", "v")); err != nil {
+		t.Fatalf("SendLog: %v", err)
+	}
+
+	deadline := time.Now().Add(2 * time.Second)
+	for client.callCount() == 0 && time.Now().Before(deadline) {
+		time.Sleep(10 * time.Millisecond)
+	}
+	if client.callCount() != 1 {
+		t.Fatalf("expected the batch to eventually succeed after retries, got %d calls", client.callCount())
+	}
+}
+
+func TestProducer_SendLogFailsWhenBufferFullAndNonBlocking(t *testing.T) {
+	client := &fakeClient{}
+	p := NewProducer(client, ProducerConfig{
+		MaxBatchCount:    1000,
+		LingerMs:         60000,
+		IoWorkerCount:    1,
+		TotalSizeLnBytes: 100,
+		BlockOnFull:      false,
+	})
+	defer p.Close(time.Second)
+
+	// Exhaust the budget directly so SendLog observes a transiently full
+	// buffer, as opposed to a single entry that could never fit.
+	if err := p.budget.acquire(100, false); err != nil {
+		t.Fatalf("acquire: %v", err)
+	}
+	defer p.budget.release(100)
+
+	err := p.SendLog("proj", "store", "", "topic", "source", newTestLog("key", "value"))
+	if !errors.Is(err, ErrProducerBufferFull) {
+		t.Fatalf("expected ErrProducerBufferFull, got %v", err)
+	}
+}
+
+func TestProducer_SendLogRejectsEntryLargerThanBudget(t *testing.T) {
+	client := &fakeClient{}
+	p := NewProducer(client, ProducerConfig{
+		MaxBatchCount:    1000,
+		LingerMs:         60000,
+		IoWorkerCount:    1,
+		TotalSizeLnBytes: 1,
+		BlockOnFull:      true,
+	})
+	defer p.Close(time.Second)
+
+	// A single entry's estimated size already exceeds the total budget, so
+	// no amount of waiting for release could ever admit it; acquire must
+	// reject it immediately instead of blocking forever, even with
+	// BlockOnFull set.
+	done := make(chan error, 1)
+	go func() {
+		done <- p.SendLog("proj", "store", "", "topic", "source", newTestLog("key", "value"))
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, ErrProducerEntryTooLarge) {
+			t.Fatalf("expected ErrProducerEntryTooLarge, got %v", err)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("SendLog blocked forever on an entry larger than the total budget")
+	}
+}
+
+func TestProducer_CloseFlushesPendingBatches(t *testing.T) {
+	client := &fakeClient{}
+	p := NewProducer(client, ProducerConfig{MaxBatchCount: 1000, LingerMs: 60000, IoWorkerCount: 2})
+
+	for i := 0; i < 5; i++ {
+		if err := p.SendLog("proj", "store", "", "topic", "source", newTestLog("k", "v")); err != nil {
+			t.Fatalf("SendLog: %v", err)
+		}
+	}
+
+	if err := p.Close(2 * time.Second); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if client.callCount() != 1 {
+		t.Fatalf("expected a single flushed batch on close, got %d", client.callCount())
+	}
+
+	if err := p.SendLog("proj", "store", "", "topic", "source", newTestLog("k", "v")); !errors.Is(err, ErrProducerClosed) {
+		t.Fatalf("expected ErrProducerClosed after Close, got %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package sls
+
+import (
+	"errors"
+	"sync"
+)
+
+// ErrProducerEntryTooLarge is returned when a single entry's size exceeds
+// the semaphore's total byte budget -- no amount of waiting for release
+// could ever admit it, so acquire rejects it outright instead of blocking
+// forever.
+var ErrProducerEntryTooLarge = errors.New("sls: log entry exceeds the producer's total byte budget")
+
+// sizeSemaphore is a bounded byte-budget semaphore: acquire blocks (or
+// fails immediately, depending on the caller) once `inUse` would exceed
+// `total`, giving Producer back-pressure under memory pressure.
+type sizeSemaphore struct {
+	mu    sync.Mutex
+	cond  *sync.Cond
+	total int64
+	inUse int64
+}
+
+func newSizeSemaphore(total int64) *sizeSemaphore {
+	s := &sizeSemaphore{total: total}
+	s.cond = sync.NewCond(&s.mu)
+	return s
+}
+
+func (s *sizeSemaphore) acquire(n int64, block bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if n > s.total {
+		return ErrProducerEntryTooLarge
+	}
+	for s.inUse+n > s.total {
+		if !block {
+			return ErrProducerBufferFull
+		}
+		s.cond.Wait()
+	}
+	s.inUse += n
+	return nil
+}
+
+func (s *sizeSemaphore) release(n int64) {
+	s.mu.Lock()
+	s.inUse -= n
+	s.mu.Unlock()
+	s.cond.Broadcast()
+}